@@ -0,0 +1,76 @@
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	strfmt "github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+
+	"github.com/go-openapi/errors"
+)
+
+// Dataset dataset
+// swagger:model dataset
+type Dataset struct {
+
+	// Alias attached to the restored indices of the dataset.
+	Alias string `json:"alias,omitempty"`
+
+	// Index settings of the snapshot that are not restored for the dataset.
+	IgnoreIndexSettings []string `json:"ignore_index_settings"`
+
+	// Restore the aliases of the indices of the dataset, unset uses the cluster default.
+	IncludeAliases *bool `json:"include_aliases,omitempty"`
+
+	// Restore the cluster state of the snapshots of the dataset, unset uses the cluster default.
+	IncludeGlobalState *bool `json:"include_global_state,omitempty"`
+
+	// Index settings of the restored indices of the dataset, keyed by full setting name.
+	IndexSettings map[string]string `json:"index_settings,omitempty"`
+
+	// Name of the dataset.
+	Name string `json:"name,omitempty"`
+
+	// Regex the restored index names of the dataset are matched with.
+	RenamePattern string `json:"rename_pattern,omitempty"`
+
+	// Replacement of the restored index names matched by rename_pattern.
+	RenameReplacement string `json:"rename_replacement,omitempty"`
+
+	// Snapshot repo pattern (repo/snap/index) of the dataset.
+	RepoPattern string `json:"repo_pattern,omitempty"`
+
+	// Index resolution of the dataset.
+	Resolution string `json:"resolution,omitempty"`
+
+	// IANA timezone index names of the dataset are computed in.
+	Timezone string `json:"timezone,omitempty"`
+
+	// Time to keep restored indices of the dataset before they are deleted.
+	TTL string `json:"ttl,omitempty"`
+}
+
+// Validate validates this dataset
+func (m *Dataset) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateIgnoreIndexSettings(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *Dataset) validateIgnoreIndexSettings(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.IgnoreIndexSettings) { // not required
+		return nil
+	}
+
+	return nil
+}
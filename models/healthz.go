@@ -14,6 +14,12 @@ import (
 // swagger:model healthz
 type Healthz struct {
 
+	// Number of indices currently being restored.
+	ActiveRestores int64 `json:"active_restores,omitempty"`
+
+	// Maximum number of indices restored concurrently.
+	MaxRestore int64 `json:"max_restore,omitempty"`
+
 	// message
 	// Required: true
 	// Min Length: 1
@@ -14,33 +14,63 @@ import (
 // swagger:model indice_status
 type IndiceStatus struct {
 
+	// List of indices queued for restore that are deferred until there is enough disk on the cluster.
+	Deferred []string `json:"deferred"`
+
+	// Reason each deferred index is waiting, keyed by index.
+	DeferredReasons map[string]string `json:"deferred_reasons,omitempty"`
+
 	// List of indices that are being deleted.
 	Deleting []string `json:"deleting"`
 
+	// List of indices restored from a PARTIAL snapshot, some of their shards may be missing.
+	Partial []string `json:"partial"`
+
 	// List of indices that are available not but being restored.
 	Pending []string `json:"pending"`
 
+	// Recovery progress of each index being restored, keyed by index.
+	Progress map[string]RestoreProgress `json:"progress,omitempty"`
+
 	// List of indices restored and are ready for query.
 	Ready []string `json:"ready"`
 
 	// List of indices being resotred.
 	Restoring []string `json:"restoring"`
+
+	// Name of the snapshot each index is restored from, keyed by index.
+	Snapshots map[string]string `json:"snapshots,omitempty"`
 }
 
 // Validate validates this indice status
 func (m *IndiceStatus) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateDeferred(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateDeleting(formats); err != nil {
 		// prop
 		res = append(res, err)
 	}
 
+	if err := m.validatePartial(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validatePending(formats); err != nil {
 		// prop
 		res = append(res, err)
 	}
 
+	if err := m.validateProgress(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateReady(formats); err != nil {
 		// prop
 		res = append(res, err)
@@ -57,6 +87,15 @@ func (m *IndiceStatus) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *IndiceStatus) validateDeferred(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Deferred) { // not required
+		return nil
+	}
+
+	return nil
+}
+
 func (m *IndiceStatus) validateDeleting(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.Deleting) { // not required
@@ -66,6 +105,15 @@ func (m *IndiceStatus) validateDeleting(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *IndiceStatus) validatePartial(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Partial) { // not required
+		return nil
+	}
+
+	return nil
+}
+
 func (m *IndiceStatus) validatePending(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.Pending) { // not required
@@ -75,6 +123,23 @@ func (m *IndiceStatus) validatePending(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *IndiceStatus) validateProgress(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Progress) { // not required
+		return nil
+	}
+
+	for k := range m.Progress {
+		if val, ok := m.Progress[k]; ok {
+			if err := val.Validate(formats); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func (m *IndiceStatus) validateReady(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.Ready) { // not required
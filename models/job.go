@@ -0,0 +1,121 @@
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	strfmt "github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+
+	"github.com/go-openapi/errors"
+)
+
+// Job job
+// swagger:model job
+type Job struct {
+
+	// List of indices of this job that have been processed.
+	Completed []string `json:"completed"`
+
+	// Time the job was created, unix timestamp
+	Created int64 `json:"created,omitempty"`
+
+	// Requested end time, unix timestamp
+	End int64 `json:"end,omitempty"`
+
+	// List of errors encountered while processing the job.
+	Errors []string `json:"errors"`
+
+	// Job ID
+	ID string `json:"id,omitempty"`
+
+	// List of indices queued by this job.
+	Indices []string `json:"indices"`
+
+	// Repo pattern used for the request.
+	RepoPattern string `json:"repo_pattern,omitempty"`
+
+	// List of indices in the requested range, including indices that did not need to be queued.
+	Requested []string `json:"requested"`
+
+	// Index resolution used for the request.
+	Resolution string `json:"resolution,omitempty"`
+
+	// Requested start time, unix timestamp
+	Start int64 `json:"start,omitempty"`
+
+	// State of the job, 'queued', 'running', 'completed', 'failed' or 'cancelled'.
+	State string `json:"state,omitempty"`
+
+	// Type of job, 'restore' or 'delete'.
+	Type string `json:"type,omitempty"`
+
+	// Time the job was last updated, unix timestamp
+	Updated int64 `json:"updated,omitempty"`
+}
+
+// Validate validates this job
+func (m *Job) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateCompleted(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateErrors(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateIndices(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateRequested(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *Job) validateCompleted(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Completed) { // not required
+		return nil
+	}
+
+	return nil
+}
+
+func (m *Job) validateErrors(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Errors) { // not required
+		return nil
+	}
+
+	return nil
+}
+
+func (m *Job) validateIndices(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Indices) { // not required
+		return nil
+	}
+
+	return nil
+}
+
+func (m *Job) validateRequested(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Requested) { // not required
+		return nil
+	}
+
+	return nil
+}
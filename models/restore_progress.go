@@ -0,0 +1,40 @@
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+)
+
+// RestoreProgress restore progress
+// swagger:model restore_progress
+type RestoreProgress struct {
+
+	// Bytes of the index recovered.
+	BytesRecovered int64 `json:"bytes_recovered,omitempty"`
+
+	// Total bytes of the index.
+	BytesTotal int64 `json:"bytes_total,omitempty"`
+
+	// Estimated seconds until the index is recovered.
+	EtaSeconds int64 `json:"eta_seconds,omitempty"`
+
+	// Percent of the index bytes recovered by the primary shards.
+	Percent float64 `json:"percent,omitempty"`
+
+	// True if the recovery has not made progress for a while.
+	Stalled bool `json:"stalled,omitempty"`
+}
+
+// Validate validates this restore progress
+func (m *RestoreProgress) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/validate"
+)
+
+// Settings settings
+// swagger:model settings
+type Settings struct {
+
+	// Maximum number of indices restored concurrently.
+	// Required: true
+	// Minimum: 1
+	MaxRestore *int64 `json:"max_restore"`
+}
+
+// Validate validates this settings
+func (m *Settings) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateMaxRestore(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *Settings) validateMaxRestore(formats strfmt.Registry) error {
+
+	if err := validate.Required("max_restore", "body", m.MaxRestore); err != nil {
+		return err
+	}
+
+	if err := validate.MinimumInt("max_restore", "body", int64(*m.MaxRestore), 1, false); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+)
+
+// SnapshotCatalogRepo snapshot catalog repo
+// swagger:model snapshot_catalog_repo
+type SnapshotCatalogRepo struct {
+
+	// Number of distinct indices in the snapshots of the repo.
+	Indices int64 `json:"indices,omitempty"`
+
+	// Time the repo was last refreshed from the cluster, unix timestamp
+	Refreshed int64 `json:"refreshed,omitempty"`
+
+	// Name of the snapshot repo.
+	Repo string `json:"repo,omitempty"`
+
+	// Number of snapshots in the repo.
+	Snapshots int64 `json:"snapshots,omitempty"`
+}
+
+// Validate validates this snapshot catalog repo
+func (m *SnapshotCatalogRepo) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package restapi
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	errors "github.com/go-openapi/errors"
+)
+
+// How often restored indices are checked for an alias to attach.
+const aliasPollInterval = 10 * time.Second
+
+// Returns an error if the alias is not a valid index name, an empty alias is valid and attaches nothing.
+func validateAlias(alias string) error {
+	if alias == "" {
+		return nil
+	}
+	if alias != strings.ToLower(alias) || strings.ContainsAny(alias, `\/*?"<>| ,#:`) ||
+		strings.HasPrefix(alias, "_") || strings.HasPrefix(alias, "-") || strings.HasPrefix(alias, "+") ||
+		alias == "." || alias == ".." || len(alias) > 255 {
+		return errors.New(400, fmt.Sprintf("Invalid alias, expected a lowercase index name: %s", alias))
+	}
+	return nil
+}
+
+// AliasManager attaches the alias of each restored index once the index is green and detaches it before the
+// index is deleted, so every restored index can be queried through the alias.
+type AliasManager struct {
+	// Alias attached to each index, keyed by repo/snap/index path.
+	attached map[string]string
+	// Indices being deleted, aliases are not attached to them.
+	detaching map[string]bool
+	mu        sync.Mutex
+}
+
+var aliasManager = NewAliasManager()
+
+func NewAliasManager() *AliasManager {
+	return &AliasManager{attached: make(map[string]string), detaching: make(map[string]bool)}
+}
+
+// Attach attaches the given alias to an index if it is not attached yet. An alias the index had before is
+// detached first.
+func (m *AliasManager) Attach(indice string, alias string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.detaching[indice] || m.attached[indice] == alias {
+		return
+	}
+
+	name := restoredName(indice)
+	if old, ok := m.attached[indice]; ok {
+		if err := backend.DeleteAlias(context.Background(), name, old); err != nil {
+			log.Println(fmt.Sprintf("WARN: could not detach alias: %s from index: %s, error: %s", old, name, err))
+			return
+		}
+		delete(m.attached, indice)
+	}
+
+	if err := backend.PutAlias(context.Background(), name, alias); err != nil {
+		log.Println(fmt.Sprintf("WARN: could not attach alias: %s to index: %s, error: %s", alias, name, err))
+		return
+	}
+	m.attached[indice] = alias
+	log.Println(fmt.Sprintf("Attached alias: %s to index: %s", alias, name))
+}
+
+// Detach detaches the alias of an index that is about to be deleted, no alias is attached to the index
+// until Done is called.
+func (m *AliasManager) Detach(indice string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.detaching[indice] = true
+
+	// Aliases attached before a restart are only known from the restore options of the index.
+	alias, ok := m.attached[indice]
+	if !ok {
+		alias = restoreOptionsStore.Get(indice).Alias
+	}
+	if alias == "" {
+		return
+	}
+
+	name := restoredName(indice)
+	if err := backend.DeleteAlias(context.Background(), name, alias); err != nil {
+		log.Println(fmt.Sprintf("WARN: could not detach alias: %s from index: %s, error: %s", alias, name, err))
+	} else {
+		log.Println(fmt.Sprintf("Detached alias: %s from index: %s", alias, name))
+	}
+	delete(m.attached, indice)
+}
+
+// Done lets aliases be attached to an index again, after its delete finished or failed.
+func (m *AliasManager) Done(indice string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.detaching, indice)
+}
+
+// Attaches the alias of every restored index that is green and has an alias in its restore options.
+func attachAliases() {
+	aliases := restoreOptionsStore.Aliases()
+	if len(aliases) == 0 {
+		return
+	}
+
+	onlineIndices, err := backend.CatIndices(context.Background())
+	if err != nil {
+		log.Println(fmt.Sprintf("WARN: could not get online indices to attach aliases: %s", err))
+		return
+	}
+	health := make(map[string]string)
+	for _, i := range onlineIndices {
+		health[i.Index] = i.Health
+	}
+
+	for indice, alias := range aliases {
+		if health[restoredName(indice)] == "green" && !deleteQueue.Contains(indice) {
+			aliasManager.Attach(indice, alias)
+		}
+	}
+}
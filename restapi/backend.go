@@ -0,0 +1,366 @@
+package restapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	errors "github.com/go-openapi/errors"
+)
+
+// Backend is every call esio makes to the Elasticsearch cluster. Handlers and workers only reach the
+// cluster through the package level backend so they can be run against an in-memory fake.
+type Backend interface {
+	// Version returns the distribution and version of the cluster.
+	Version(ctx context.Context) (ClusterVersion, error)
+	// ClusterHealth returns the cluster health status: green, yellow or red.
+	ClusterHealth(ctx context.Context) (string, error)
+	// RepoExists checks that the snapshot repo is registered.
+	RepoExists(ctx context.Context, repo string) error
+	// ListSnapshots returns the snapshots of a repo, or the single snapshot of a repo/snap path.
+	// Snapshots that don't exist give an empty list.
+	ListSnapshots(ctx context.Context, name string) ([]Snapshot, error)
+	// SnapshotIndexSizes returns the size in bytes of each index of a snapshot (repo/snap), keyed by index name.
+	SnapshotIndexSizes(ctx context.Context, snapshot string) (map[string]int64, error)
+	// Restore starts the restore of the given indices from a snapshot (repo/snap), the recovery of the
+	// indices is followed with RecoveryStatus.
+	Restore(ctx context.Context, snapshot string, indices []string, opts RestoreOptions) error
+	// RecoveryStatus returns the shard recoveries of the given indices, keyed by index name.
+	RecoveryStatus(ctx context.Context, indices []string) (map[string][]ShardRecovery, error)
+	// CatIndices returns the indices on the cluster.
+	CatIndices(ctx context.Context) ([]CatIndex, error)
+	// DeleteIndex deletes an index from the cluster.
+	DeleteIndex(ctx context.Context, index string) error
+	// PutAlias attaches an alias to an index.
+	PutAlias(ctx context.Context, index string, alias string) error
+	// DeleteAlias detaches an alias from an index, an alias that is not attached is not an error.
+	DeleteAlias(ctx context.Context, index string, alias string) error
+	// DiskUsage returns the total and available disk in bytes summed over all data nodes.
+	DiskUsage(ctx context.Context) (int64, int64, error)
+}
+
+var backend Backend = NewHTTPBackend()
+
+// Attempts of a GET request, and the wait before the first retry which doubles after each attempt.
+const retryAttempts = 4
+const retryBackoff = 500 * time.Millisecond
+
+type ClusterHealthResponse struct {
+	Status string `json:"status"`
+}
+
+type IndexRecovery struct {
+	Shards []ShardRecovery `json:"shards"`
+}
+
+// ShardRecovery is the recovery of one shard, the stage is DONE once the shard is recovered.
+type ShardRecovery struct {
+	ID      int    `json:"id"`
+	Type    string `json:"type"`
+	Stage   string `json:"stage"`
+	Primary bool   `json:"primary"`
+	Index   struct {
+		Size struct {
+			TotalInBytes     int64 `json:"total_in_bytes"`
+			RecoveredInBytes int64 `json:"recovered_in_bytes"`
+		} `json:"size"`
+	} `json:"index"`
+}
+
+// HTTPBackend calls the REST API of the cluster at the es-host of the current config. The version of the
+// cluster is detected on the first call and again whenever the es-host changes, requests and responses
+// that differ between versions go through the adapter of the version.
+type HTTPBackend struct {
+	host    string
+	version ClusterVersion
+	adapter *apiAdapter
+	mu      sync.Mutex
+}
+
+func NewHTTPBackend() *HTTPBackend {
+	return &HTTPBackend{}
+}
+
+func (b *HTTPBackend) Version(ctx context.Context) (ClusterVersion, error) {
+	_, version, err := b.api(ctx)
+	return version, err
+}
+
+// Returns the adapter for the version of the cluster at the current es-host, detecting it if needed.
+// The lock is not held while the version is detected so a slow or unreachable cluster only holds up the
+// requests that are waiting for it, each of them detects the version within its own context.
+func (b *HTTPBackend) api(ctx context.Context) (*apiAdapter, ClusterVersion, error) {
+	host := currentConfig().Elasticsearch.Host
+
+	b.mu.Lock()
+	if b.adapter != nil && b.host == host {
+		adapter, version := b.adapter, b.version
+		b.mu.Unlock()
+		return adapter, version, nil
+	}
+	b.mu.Unlock()
+
+	var info ClusterInfoResponse
+	if err := b.do(ctx, "GET", "/", nil, &info); err != nil {
+		return nil, ClusterVersion{}, err
+	}
+	version, err := parseClusterVersion(info)
+	if err != nil {
+		return nil, version, err
+	}
+	adapter, err := adapterFor(version)
+	if err != nil {
+		return nil, version, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// A version detected for a host that has since been replaced by a reload is not kept.
+	if b.host != host || b.adapter == nil {
+		if host == currentConfig().Elasticsearch.Host {
+			log.Println(fmt.Sprintf("Detected cluster version: %s at: %s, using %s API", version, host, adapter.name))
+			b.host, b.version, b.adapter = host, version, adapter
+		}
+	}
+	return adapter, version, nil
+}
+
+func (b *HTTPBackend) ClusterHealth(ctx context.Context) (string, error) {
+	var health ClusterHealthResponse
+	if err := b.do(ctx, "GET", "/_cluster/health", nil, &health); err != nil {
+		return "", err
+	}
+	return health.Status, nil
+}
+
+func (b *HTTPBackend) RepoExists(ctx context.Context, repo string) error {
+	return b.do(ctx, "GET", fmt.Sprintf("/_snapshot/%s", repo), nil, nil)
+}
+
+func (b *HTTPBackend) ListSnapshots(ctx context.Context, name string) ([]Snapshot, error) {
+	api, _, err := b.api(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// A repo alone lists its settings, not its snapshots.
+	if !strings.Contains(name, "/") {
+		name += "/_all"
+	}
+	uri := fmt.Sprintf("/_snapshot/%s", name)
+	if api.listSnapshotsQuery != "" {
+		uri += "?" + api.listSnapshotsQuery
+	}
+
+	var snap SnapshotResponse
+	err = b.do(ctx, "GET", uri, nil, &snap)
+	if e, ok := err.(errors.Error); ok && e.Code() == http.StatusNotFound {
+		return []Snapshot{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return snap.Snapshots, nil
+}
+
+func (b *HTTPBackend) SnapshotIndexSizes(ctx context.Context, snapshot string) (map[string]int64, error) {
+	sizes := make(map[string]int64)
+
+	var status SnapshotStatusResponse
+	if err := b.do(ctx, "GET", fmt.Sprintf("/_snapshot/%s/_status", snapshot), nil, &status); err != nil {
+		return sizes, err
+	}
+
+	for _, snap := range status.Snapshots {
+		for name, indice := range snap.Indices {
+			sizes[name] = indice.Stats.Size()
+		}
+	}
+	return sizes, nil
+}
+
+func (b *HTTPBackend) Restore(ctx context.Context, snapshot string, indices []string, opts RestoreOptions) error {
+	api, _, err := b.api(ctx)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(api.restoreBody(indices, opts))
+	if err != nil {
+		return errors.New(500, fmt.Sprintf("Error encoding restore request body: %s", err))
+	}
+
+	var accepted RestoreAcceptedResponse
+	if err := b.do(ctx, "POST", fmt.Sprintf("/_snapshot/%s/_restore", snapshot), data, &accepted); err != nil {
+		return err
+	}
+	if !accepted.Accepted {
+		return errors.New(500, fmt.Sprintf("Restore of snapshot '%s' was not accepted", snapshot))
+	}
+	return nil
+}
+
+func (b *HTTPBackend) RecoveryStatus(ctx context.Context, indices []string) (map[string][]ShardRecovery, error) {
+	recoveries := make(map[string][]ShardRecovery)
+
+	res := make(map[string]IndexRecovery)
+	if err := b.do(ctx, "GET", fmt.Sprintf("/%s/_recovery?ignore_unavailable=true", strings.Join(indices, ",")), nil, &res); err != nil {
+		return recoveries, err
+	}
+
+	for name, recovery := range res {
+		recoveries[name] = recovery.Shards
+	}
+	return recoveries, nil
+}
+
+func (b *HTTPBackend) CatIndices(ctx context.Context) ([]CatIndex, error) {
+	cat := make([]CatIndex, 0)
+
+	api, _, err := b.api(ctx)
+	if err != nil {
+		return cat, err
+	}
+
+	err = b.do(ctx, "GET", "/_cat/indices?"+api.catIndicesQuery, nil, &cat)
+	return cat, err
+}
+
+func (b *HTTPBackend) DeleteIndex(ctx context.Context, index string) error {
+	return b.do(ctx, "DELETE", fmt.Sprintf("/%s", index), nil, nil)
+}
+
+func (b *HTTPBackend) PutAlias(ctx context.Context, index string, alias string) error {
+	return b.do(ctx, "PUT", fmt.Sprintf("/%s/_alias/%s", index, alias), nil, nil)
+}
+
+func (b *HTTPBackend) DeleteAlias(ctx context.Context, index string, alias string) error {
+	err := b.do(ctx, "DELETE", fmt.Sprintf("/%s/_alias/%s", index, alias), nil, nil)
+	if e, ok := err.(errors.Error); ok && e.Code() == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+func (b *HTTPBackend) DiskUsage(ctx context.Context) (int64, int64, error) {
+	var total, avail int64
+
+	allocation := make([]CatAllocation, 0)
+	if err := b.do(ctx, "GET", "/_cat/allocation?format=json&bytes=b", nil, &allocation); err != nil {
+		return total, avail, err
+	}
+
+	for _, node := range allocation {
+		// Unassigned shards are listed without disk stats.
+		if node.DiskTotal == "" {
+			continue
+		}
+		nodeTotal, err := strconv.ParseInt(node.DiskTotal, 10, 64)
+		if err != nil {
+			return total, avail, errors.New(500, fmt.Sprintf("Invalid disk.total for node '%s': %s", node.Node, node.DiskTotal))
+		}
+		nodeAvail, err := strconv.ParseInt(node.DiskAvail, 10, 64)
+		if err != nil {
+			return total, avail, errors.New(500, fmt.Sprintf("Invalid disk.avail for node '%s': %s", node.Node, node.DiskAvail))
+		}
+		total += nodeTotal
+		avail += nodeAvail
+	}
+
+	if total == 0 {
+		return total, avail, errors.New(500, "No data node disk stats found in _cat/allocation")
+	}
+
+	return total, avail, nil
+}
+
+// Makes a request to the cluster within the request timeout of the config and decodes the JSON response
+// into v if it is not nil. Responses other than 200 are returned as an error with the status code of the response.
+func (b *HTTPBackend) do(ctx context.Context, method string, uri string, body []byte, v interface{}) error {
+	return b.doTimeout(ctx, currentConfig().timeout, method, uri, body, v)
+}
+
+// Like do with the given timeout, which covers every attempt. GET requests are idempotent and are retried
+// with exponential backoff while the cluster can't be reached or is overloaded.
+func (b *HTTPBackend) doTimeout(ctx context.Context, timeout time.Duration, method string, uri string, body []byte, v interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	backoff := retryBackoff
+	for attempt := 1; ; attempt++ {
+		retry, err := b.send(ctx, method, uri, body, v)
+		if err == nil || !retry || method != "GET" || attempt == retryAttempts {
+			return err
+		}
+
+		log.Println(fmt.Sprintf("Retrying %s %s in %s, attempt %d/%d: %s", method, uri, backoff, attempt+1, retryAttempts, err))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// Makes a single request, returns true with the error if the request can be retried.
+func (b *HTTPBackend) send(ctx context.Context, method string, uri string, body []byte, v interface{}) (bool, error) {
+	cfg := currentConfig()
+	endpoint := cfg.Elasticsearch.Host + uri
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequest(method, endpoint, reader)
+	if err != nil {
+		return false, errors.New(500, fmt.Sprintf("Error building http request: %s", err))
+	}
+	req = req.WithContext(ctx)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if cfg.authorization != "" {
+		req.Header.Set("Authorization", cfg.authorization)
+	}
+
+	resp, err := cfg.client.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return false, errors.New(504, fmt.Sprintf("%s request timed out for url: %s", method, endpoint))
+		}
+		if ctx.Err() != nil {
+			return false, errors.New(500, fmt.Sprintf("%s request cancelled for url: %s", method, endpoint))
+		}
+		return true, errors.New(500, fmt.Sprintf("Error making client request: %s", err))
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := ioutil.ReadAll(resp.Body)
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusBadGateway ||
+			resp.StatusCode == http.StatusServiceUnavailable || resp.StatusCode == http.StatusGatewayTimeout
+		return retry, errors.New(int32(resp.StatusCode), fmt.Sprintf("%s request failed for url: %s: %s", method, endpoint, data))
+	}
+
+	if v == nil {
+		return false, nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return false, errors.New(500, fmt.Sprintf("Error decoding ES JSON response for url: %s", endpoint))
+	}
+	return false, nil
+}
@@ -0,0 +1,484 @@
+package restapi
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/danisla/esio/models"
+)
+
+// How often the config file is checked for changes.
+const configWatchInterval = 5 * time.Second
+
+// Config is the effective server configuration. Settings come from the --config file, env vars and flags,
+// each overriding the one before. The whole config is replaced when the config file is reloaded.
+// See config.example.yml for the file format.
+type Config struct {
+	Elasticsearch ElasticsearchConfig      `yaml:"elasticsearch"`
+	Indices       IndicesConfig            `yaml:"indices"`
+	Queues        QueuesConfig             `yaml:"queues"`
+	Limits        LimitsConfig             `yaml:"limits"`
+	Snapshots     SnapshotsConfig          `yaml:"snapshots"`
+	Datasets      map[string]DatasetConfig `yaml:"datasets"`
+
+	// Parsed settings, set by validate.
+	authorization   string
+	transport       *http.Transport
+	client          *http.Client
+	timeout         time.Duration
+	restoreTimeout  time.Duration
+	stallTimeout    time.Duration
+	location        *time.Location
+	defaultTTL      time.Duration
+	snapshotRefresh time.Duration
+	datasets        map[string]*models.Dataset
+}
+
+type ElasticsearchConfig struct {
+	Host            string    `yaml:"host"`
+	Username        string    `yaml:"username"`
+	Password        string    `yaml:"password"`
+	PasswordFile    string    `yaml:"password_file"`
+	APIKey          string    `yaml:"api_key"`
+	APIKeyFile      string    `yaml:"api_key_file"`
+	BearerToken     string    `yaml:"bearer_token"`
+	BearerTokenFile string    `yaml:"bearer_token_file"`
+	TLS             TLSConfig `yaml:"tls"`
+	Timeout         string    `yaml:"timeout"`
+	RestoreTimeout  string    `yaml:"restore_timeout"`
+	// Time a recovery can go without progress before its restore fails, 0 never fails stalled recoveries.
+	RecoveryStallTimeout string `yaml:"recovery_stall_timeout"`
+}
+
+type IndicesConfig struct {
+	Resolution  string `yaml:"resolution"`
+	RepoPattern string `yaml:"repo_pattern"`
+	Timezone    string `yaml:"timezone"`
+	// Restore indices from a PARTIAL snapshot when they have no SUCCESS snapshot.
+	AcceptPartial bool `yaml:"accept_partial"`
+	// Alias attached to restored indices once they are green.
+	Alias string `yaml:"alias"`
+}
+
+type QueuesConfig struct {
+	StateDir   string `yaml:"state_dir"`
+	MaxRestore int    `yaml:"max_restore"`
+}
+
+type LimitsConfig struct {
+	DiskWatermark      int    `yaml:"disk_watermark"`
+	MaxRestoredBytes   int64  `yaml:"max_restored_bytes"`
+	MaxRestoredIndices int    `yaml:"max_restored_indices"`
+	DefaultTTL         string `yaml:"default_ttl"`
+}
+
+type SnapshotsConfig struct {
+	RefreshInterval string `yaml:"refresh_interval"`
+}
+
+var config *Config
+var configMu sync.RWMutex
+
+// Returns the current config, it must not be modified.
+func currentConfig() *Config {
+	configMu.RLock()
+	defer configMu.RUnlock()
+
+	return config
+}
+
+func setConfig(c *Config) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	config = c
+}
+
+// Builds the config from the config file, env vars and flags and validates it.
+func loadConfig() (*Config, error) {
+	c := &Config{}
+
+	if myFlags.Config == "" {
+		myFlags.Config = os.Getenv("CONFIG")
+	}
+	if myFlags.Config != "" {
+		data, err := ioutil.ReadFile(myFlags.Config)
+		if err != nil {
+			return nil, &startupError{exitInvalidConfig, "Invalid configuration", []string{fmt.Sprintf("error reading config file '%s': %s", myFlags.Config, err)}}
+		}
+		if err := yaml.Unmarshal(data, c); err != nil {
+			return nil, &startupError{exitInvalidConfig, "Invalid configuration", []string{fmt.Sprintf("error decoding config file '%s': %s", myFlags.Config, err)}}
+		}
+	}
+
+	problems := make([]string, 0)
+
+	// Env vars override the config file.
+	envString := func(name string, value *string) {
+		if os.Getenv(name) != "" {
+			*value = os.Getenv(name)
+		}
+	}
+	envInt := func(name string, value *int) {
+		if os.Getenv(name) != "" {
+			v, err := strconv.Atoi(os.Getenv(name))
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("invalid %s env provided: %s", name, os.Getenv(name)))
+			}
+			*value = v
+		}
+	}
+	// A secret value replaces a secret file set before it and the other way around.
+	envSecret := func(name string, value *string, file *string) {
+		if os.Getenv(name) != "" {
+			*value, *file = os.Getenv(name), ""
+		}
+		if os.Getenv(name+"_FILE") != "" {
+			*value, *file = "", os.Getenv(name+"_FILE")
+		}
+	}
+	envString("ES_HOST", &c.Elasticsearch.Host)
+	envString("ES_USERNAME", &c.Elasticsearch.Username)
+	envSecret("ES_PASSWORD", &c.Elasticsearch.Password, &c.Elasticsearch.PasswordFile)
+	envSecret("ES_API_KEY", &c.Elasticsearch.APIKey, &c.Elasticsearch.APIKeyFile)
+	envSecret("ES_BEARER_TOKEN", &c.Elasticsearch.BearerToken, &c.Elasticsearch.BearerTokenFile)
+	envString("ES_TIMEOUT", &c.Elasticsearch.Timeout)
+	envString("ES_RESTORE_TIMEOUT", &c.Elasticsearch.RestoreTimeout)
+	envString("ES_RECOVERY_STALL_TIMEOUT", &c.Elasticsearch.RecoveryStallTimeout)
+	envString("ES_CA_FILE", &c.Elasticsearch.TLS.CAFile)
+	envString("ES_CERT_FILE", &c.Elasticsearch.TLS.CertFile)
+	envString("ES_KEY_FILE", &c.Elasticsearch.TLS.KeyFile)
+	envString("INDEX_RESOLUTION", &c.Indices.Resolution)
+	envString("REPO_PATTERN", &c.Indices.RepoPattern)
+	envString("TIMEZONE", &c.Indices.Timezone)
+	envString("ALIAS", &c.Indices.Alias)
+	if os.Getenv("ACCEPT_PARTIAL") != "" {
+		v, err := strconv.ParseBool(os.Getenv("ACCEPT_PARTIAL"))
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("invalid ACCEPT_PARTIAL env provided: %s", os.Getenv("ACCEPT_PARTIAL")))
+		}
+		c.Indices.AcceptPartial = v
+	}
+	envString("STATE_DIR", &c.Queues.StateDir)
+	envInt("MAX_RESTORE", &c.Queues.MaxRestore)
+	envInt("DISK_WATERMARK", &c.Limits.DiskWatermark)
+	envInt("MAX_RESTORED_INDICES", &c.Limits.MaxRestoredIndices)
+	envString("DEFAULT_TTL", &c.Limits.DefaultTTL)
+	envString("SNAPSHOT_REFRESH_INTERVAL", &c.Snapshots.RefreshInterval)
+	if os.Getenv("MAX_RESTORED_BYTES") != "" {
+		v, err := strconv.ParseInt(os.Getenv("MAX_RESTORED_BYTES"), 10, 64)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("invalid MAX_RESTORED_BYTES env provided: %s", os.Getenv("MAX_RESTORED_BYTES")))
+		}
+		c.Limits.MaxRestoredBytes = v
+	}
+
+	// Flags override env vars.
+	if myFlags.EsHost != "" {
+		c.Elasticsearch.Host = myFlags.EsHost
+	}
+	if myFlags.EsUsername != "" {
+		c.Elasticsearch.Username = myFlags.EsUsername
+	}
+	if myFlags.EsPasswordFile != "" {
+		c.Elasticsearch.Password, c.Elasticsearch.PasswordFile = "", myFlags.EsPasswordFile
+	}
+	if myFlags.EsAPIKeyFile != "" {
+		c.Elasticsearch.APIKey, c.Elasticsearch.APIKeyFile = "", myFlags.EsAPIKeyFile
+	}
+	if myFlags.EsBearerTokenFile != "" {
+		c.Elasticsearch.BearerToken, c.Elasticsearch.BearerTokenFile = "", myFlags.EsBearerTokenFile
+	}
+	if myFlags.EsTimeout != "" {
+		c.Elasticsearch.Timeout = myFlags.EsTimeout
+	}
+	if myFlags.EsRestoreTimeout != "" {
+		c.Elasticsearch.RestoreTimeout = myFlags.EsRestoreTimeout
+	}
+	if myFlags.EsRecoveryStallTimeout != "" {
+		c.Elasticsearch.RecoveryStallTimeout = myFlags.EsRecoveryStallTimeout
+	}
+	if myFlags.EsCAFile != "" {
+		c.Elasticsearch.TLS.CAFile = myFlags.EsCAFile
+	}
+	if myFlags.EsCertFile != "" {
+		c.Elasticsearch.TLS.CertFile = myFlags.EsCertFile
+	}
+	if myFlags.EsKeyFile != "" {
+		c.Elasticsearch.TLS.KeyFile = myFlags.EsKeyFile
+	}
+	if myFlags.IndexResolution != "" {
+		c.Indices.Resolution = myFlags.IndexResolution
+	}
+	if myFlags.RepoPattern != "" {
+		c.Indices.RepoPattern = myFlags.RepoPattern
+	}
+	if myFlags.Timezone != "" {
+		c.Indices.Timezone = myFlags.Timezone
+	}
+	if myFlags.AcceptPartial {
+		c.Indices.AcceptPartial = true
+	}
+	if myFlags.Alias != "" {
+		c.Indices.Alias = myFlags.Alias
+	}
+	if myFlags.StateDir != "" {
+		c.Queues.StateDir = myFlags.StateDir
+	}
+	if myFlags.MaxRestore != 0 {
+		c.Queues.MaxRestore = myFlags.MaxRestore
+	}
+	if myFlags.DiskWatermark != 0 {
+		c.Limits.DiskWatermark = myFlags.DiskWatermark
+	}
+	if myFlags.MaxRestoredBytes != 0 {
+		c.Limits.MaxRestoredBytes = myFlags.MaxRestoredBytes
+	}
+	if myFlags.MaxRestoredIndices != 0 {
+		c.Limits.MaxRestoredIndices = myFlags.MaxRestoredIndices
+	}
+	if myFlags.DefaultTTL != "" {
+		c.Limits.DefaultTTL = myFlags.DefaultTTL
+	}
+
+	if myFlags.SnapshotRefreshInterval != "" {
+		c.Snapshots.RefreshInterval = myFlags.SnapshotRefreshInterval
+	}
+
+	if myFlags.Datasets == "" {
+		myFlags.Datasets = os.Getenv("DATASETS")
+	}
+
+	// Defaults
+	if c.Elasticsearch.Timeout == "" {
+		c.Elasticsearch.Timeout = "30s"
+	}
+	if c.Elasticsearch.RestoreTimeout == "" {
+		c.Elasticsearch.RestoreTimeout = "2h"
+	}
+	if c.Elasticsearch.RecoveryStallTimeout == "" {
+		c.Elasticsearch.RecoveryStallTimeout = "30m"
+	}
+	if c.Snapshots.RefreshInterval == "" {
+		c.Snapshots.RefreshInterval = "5m"
+	}
+	if c.Queues.MaxRestore == 0 {
+		c.Queues.MaxRestore = 1
+	}
+	if c.Limits.DiskWatermark == 0 {
+		c.Limits.DiskWatermark = 85
+	}
+
+	problems = append(problems, c.validate()...)
+
+	if len(problems) > 0 {
+		return nil, &startupError{exitInvalidConfig, "Invalid configuration", problems}
+	}
+
+	return c, nil
+}
+
+// Checks every setting and parses the ones used at runtime, it returns a description of each problem found.
+func (c *Config) validate() []string {
+	problems := make([]string, 0)
+
+	if c.Elasticsearch.Host == "" {
+		problems = append(problems, "no es-host flag, ES_HOST env or elasticsearch.host config provided")
+	}
+
+	authorization, err := c.Elasticsearch.authorization()
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+	c.authorization = authorization
+
+	transport, err := c.Elasticsearch.transport()
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+	c.transport = transport
+	c.client = &http.Client{Transport: transport}
+
+	if d, err := time.ParseDuration(c.Elasticsearch.Timeout); err != nil || d <= 0 {
+		problems = append(problems, fmt.Sprintf("invalid es-timeout: %s", c.Elasticsearch.Timeout))
+	} else {
+		c.timeout = d
+	}
+
+	if d, err := time.ParseDuration(c.Elasticsearch.RestoreTimeout); err != nil || d <= 0 {
+		problems = append(problems, fmt.Sprintf("invalid es-restore-timeout: %s", c.Elasticsearch.RestoreTimeout))
+	} else {
+		c.restoreTimeout = d
+	}
+
+	if d, err := time.ParseDuration(c.Elasticsearch.RecoveryStallTimeout); err != nil || d < 0 {
+		problems = append(problems, fmt.Sprintf("invalid es-recovery-stall-timeout: %s", c.Elasticsearch.RecoveryStallTimeout))
+	} else {
+		c.stallTimeout = d
+	}
+
+	if c.Indices.Resolution == "" {
+		problems = append(problems, "no resolution flag, INDEX_RESOLUTION env or indices.resolution config provided")
+	} else if _, err := parseResolution(c.Indices.Resolution); err != nil {
+		problems = append(problems, fmt.Sprintf("invalid resolution: %s", c.Indices.Resolution))
+	}
+
+	if c.Indices.RepoPattern == "" {
+		problems = append(problems, "no repo-pattern flag, REPO_PATTERN env or indices.repo_pattern config provided")
+	} else if err := validateRepoPattern(c.Indices.RepoPattern); err != nil {
+		problems = append(problems, fmt.Sprintf("invalid repo-pattern, expected repo/snap/index: %s", c.Indices.RepoPattern))
+	}
+
+	c.location = time.UTC
+	if c.Indices.Timezone != "" {
+		loc, err := parseTimezone(c.Indices.Timezone)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("invalid timezone: %s", c.Indices.Timezone))
+		}
+		c.location = loc
+	}
+
+	if err := validateAlias(c.Indices.Alias); err != nil {
+		problems = append(problems, fmt.Sprintf("invalid alias: %s", c.Indices.Alias))
+	}
+
+	if c.Queues.MaxRestore < 1 {
+		problems = append(problems, fmt.Sprintf("max-restore must be at least 1: %d", c.Queues.MaxRestore))
+	}
+
+	if c.Limits.DiskWatermark < 0 {
+		problems = append(problems, fmt.Sprintf("invalid disk-watermark: %d", c.Limits.DiskWatermark))
+	}
+
+	if c.Limits.MaxRestoredBytes < 0 {
+		problems = append(problems, fmt.Sprintf("invalid max-restored-bytes: %d", c.Limits.MaxRestoredBytes))
+	}
+
+	if c.Limits.MaxRestoredIndices < 0 {
+		problems = append(problems, fmt.Sprintf("invalid max-restored-indices: %d", c.Limits.MaxRestoredIndices))
+	}
+
+	if c.Limits.DefaultTTL != "" {
+		ttl, err := parseTTL(c.Limits.DefaultTTL)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("invalid default-ttl: %s", err))
+		}
+		c.defaultTTL = ttl
+	}
+
+	if d, err := time.ParseDuration(c.Snapshots.RefreshInterval); err != nil || d < 0 {
+		problems = append(problems, fmt.Sprintf("invalid snapshot-refresh-interval: %s", c.Snapshots.RefreshInterval))
+	} else {
+		c.snapshotRefresh = d
+	}
+
+	// A --datasets file replaces the datasets of the config file.
+	if myFlags.Datasets != "" {
+		loaded, err := loadDatasets(myFlags.Datasets)
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+		c.datasets = loaded
+	} else {
+		c.datasets = make(map[string]*models.Dataset)
+		names := make([]string, 0, len(c.Datasets))
+		for name := range c.Datasets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			dataset, err := makeDataset(name, c.Datasets[name])
+			if err != nil {
+				problems = append(problems, err.Error())
+				continue
+			}
+			c.datasets[name] = dataset
+		}
+	}
+
+	return problems
+}
+
+// Applies the settings of the config that are kept by other components.
+func applyConfig(c *Config) {
+	setConfig(c)
+	datasetRegistry.Set(c.datasets)
+}
+
+// Rebuilds the config and applies it, queued work is kept. An invalid config is logged and ignored.
+// The state dir can't be changed without a restart.
+func reloadConfig() {
+	old := currentConfig()
+
+	c, err := loadConfig()
+	if err != nil {
+		log.Println(fmt.Sprintf("ERROR: config not reloaded: %s", err))
+		return
+	}
+
+	if c.Queues.StateDir != old.Queues.StateDir {
+		log.Println(fmt.Sprintf("WARN: state dir can't be changed without a restart, keeping: %s", old.Queues.StateDir))
+		c.Queues.StateDir = old.Queues.StateDir
+	}
+
+	applyConfig(c)
+	old.transport.CloseIdleConnections()
+
+	// Only changed limits are applied so runtime changes from PUT /settings are kept otherwise.
+	if c.Queues.MaxRestore != old.Queues.MaxRestore {
+		restorePool.SetMax(c.Queues.MaxRestore)
+	}
+	if c.Limits.DiskWatermark != old.Limits.DiskWatermark {
+		diskGuard.SetWatermark(c.Limits.DiskWatermark)
+	}
+
+	log.Println(fmt.Sprintf("Reloaded config, datasets: %d", len(c.datasets)))
+}
+
+// Reloads the config on SIGHUP or when the config file changes.
+func watchConfig() {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		modTime := configModTime()
+		ticker := time.NewTicker(configWatchInterval)
+		for {
+			select {
+			case <-hup:
+				log.Println("Received SIGHUP, reloading config")
+				modTime = configModTime()
+				reloadConfig()
+			case <-ticker.C:
+				if t := configModTime(); !t.Equal(modTime) {
+					log.Println(fmt.Sprintf("Config file changed, reloading: %s", myFlags.Config))
+					modTime = t
+					reloadConfig()
+				}
+			}
+		}
+	}()
+}
+
+// Returns the modification time of the config file, the zero time if there is none.
+func configModTime() time.Time {
+	if myFlags.Config == "" {
+		return time.Time{}
+	}
+	info, err := os.Stat(myFlags.Config)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
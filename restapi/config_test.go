@@ -0,0 +1,199 @@
+package restapi
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Env vars read by loadConfig that the tests set.
+var configTestEnv = []string{"CONFIG", "ES_HOST", "ES_TIMEOUT", "MAX_RESTORE", "INDEX_RESOLUTION", "REPO_PATTERN", "DATASETS"}
+
+// Clears the env vars and flags read by loadConfig, sets the given env vars and returns a func that puts
+// the previous env vars and flags back.
+func setConfigEnv(env map[string]string) func() {
+	flags := myFlags
+	prev := make(map[string]string)
+	for _, name := range configTestEnv {
+		if value, ok := os.LookupEnv(name); ok {
+			prev[name] = value
+		}
+		os.Unsetenv(name)
+	}
+	for name, value := range env {
+		os.Setenv(name, value)
+	}
+	return func() {
+		myFlags = flags
+		for _, name := range configTestEnv {
+			os.Unsetenv(name)
+		}
+		for name, value := range prev {
+			os.Setenv(name, value)
+		}
+	}
+}
+
+func writeConfigFile(t *testing.T, dir string, data string) string {
+	file := filepath.Join(dir, "config.yml")
+	if err := ioutil.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatalf("Error writing config file: %s", err)
+	}
+	return file
+}
+
+const testConfigFile = `
+elasticsearch:
+  host: http://file:9200
+  timeout: 10s
+indices:
+  resolution: day
+  repo_pattern: logs-%Y/nightly-*/logs-v1-%Y-%m-%d
+queues:
+  max_restore: 2
+`
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "esio-config")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	file := writeConfigFile(t, dir, testConfigFile)
+
+	type settings struct {
+		Host       string
+		Timeout    string
+		Resolution string
+		MaxRestore int
+	}
+
+	tests := []struct {
+		name  string
+		env   map[string]string
+		flags func()
+		want  settings
+	}{
+		{"file", map[string]string{"CONFIG": file}, nil,
+			settings{"http://file:9200", "10s", "day", 2}},
+		{"env over file", map[string]string{"CONFIG": file, "ES_HOST": "http://env:9200", "MAX_RESTORE": "3"}, nil,
+			settings{"http://env:9200", "10s", "day", 3}},
+		{"flags over env", map[string]string{"CONFIG": file, "ES_HOST": "http://env:9200", "MAX_RESTORE": "3"}, func() {
+			myFlags.EsHost = "http://flag:9200"
+			myFlags.MaxRestore = 4
+			myFlags.IndexResolution = "hour"
+		}, settings{"http://flag:9200", "10s", "hour", 4}},
+		{"defaults", map[string]string{"ES_HOST": "http://env:9200", "INDEX_RESOLUTION": "day", "REPO_PATTERN": "logs/snap/logs-%Y"}, nil,
+			settings{"http://env:9200", "30s", "day", 1}},
+	}
+
+	for _, tt := range tests {
+		func() {
+			defer setConfigEnv(tt.env)()
+			myFlags.Config = ""
+			if tt.flags != nil {
+				tt.flags()
+			}
+
+			c, err := loadConfig()
+			if err != nil {
+				t.Errorf("%s: loadConfig() error: %s", tt.name, err)
+				return
+			}
+			got := settings{c.Elasticsearch.Host, c.Elasticsearch.Timeout, c.Indices.Resolution, c.Queues.MaxRestore}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s: loadConfig() = %+v, want %+v", tt.name, got, tt.want)
+			}
+		}()
+	}
+
+	invalid := []struct {
+		name string
+		env  map[string]string
+	}{
+		{"invalid env", map[string]string{"CONFIG": file, "MAX_RESTORE": "many"}},
+		{"invalid timeout", map[string]string{"CONFIG": file, "ES_TIMEOUT": "soon"}},
+		{"missing file", map[string]string{"CONFIG": filepath.Join(dir, "missing.yml")}},
+		{"missing settings", map[string]string{"ES_HOST": "http://env:9200"}},
+	}
+	for _, tt := range invalid {
+		func() {
+			defer setConfigEnv(tt.env)()
+			myFlags.Config = ""
+
+			c, err := loadConfig()
+			if err == nil {
+				t.Errorf("%s: loadConfig() = %+v, want an error", tt.name, c)
+			} else if e, ok := err.(*startupError); !ok || e.code != exitInvalidConfig {
+				t.Errorf("%s: loadConfig() error = %v, want exit code %d", tt.name, err, exitInvalidConfig)
+			}
+		}()
+	}
+}
+
+func TestReloadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "esio-config")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	file := writeConfigFile(t, dir, testConfigFile)
+
+	defer setConfigEnv(map[string]string{"CONFIG": file})()
+	myFlags.Config = ""
+	c, err := loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig() error: %s", err)
+	}
+	defer useFakeBackend(newFakeBackend(), c)()
+	restorePool.SetMax(c.Queues.MaxRestore)
+	restoreQueue.Push(&Node{"logs-2016/nightly-1/logs-v1-2016-04-07"})
+	deleteQueue.Push(&Node{"logs-2016/nightly-1/logs-v1-2016-04-06"})
+
+	// An invalid config is ignored.
+	writeConfigFile(t, dir, testConfigFile+"  bogus: [\n")
+	reloadConfig()
+	if currentConfig() != c {
+		t.Fatalf("reloadConfig() of an invalid config file replaced the config")
+	}
+	writeConfigFile(t, dir, `
+elasticsearch:
+  host: http://file:9200
+indices:
+  resolution: fortnight
+  repo_pattern: logs-%Y/nightly-*/logs-v1-%Y-%m-%d
+`)
+	reloadConfig()
+	if currentConfig() != c {
+		t.Fatalf("reloadConfig() of a config with an invalid resolution replaced the config")
+	}
+
+	// A valid config is applied, queued work is kept.
+	writeConfigFile(t, dir, `
+elasticsearch:
+  host: http://reloaded:9200
+indices:
+  resolution: hour
+  repo_pattern: logs-%Y/nightly-*/logs-v1-%Y-%m-%dT%H
+queues:
+  max_restore: 3
+`)
+	reloadConfig()
+	if got := currentConfig().Elasticsearch.Host; got != "http://reloaded:9200" {
+		t.Errorf("es host after reload = %s, want http://reloaded:9200", got)
+	}
+	if got := currentConfig().Indices.Resolution; got != "hour" {
+		t.Errorf("resolution after reload = %s, want hour", got)
+	}
+	if got := restorePool.Max(); got != 3 {
+		t.Errorf("max restore after reload = %d, want 3", got)
+	}
+	if got, want := restoreQueue.Snapshot(), []string{"logs-2016/nightly-1/logs-v1-2016-04-07"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("restore queue after reload = %v, want %v", got, want)
+	}
+	if got, want := deleteQueue.Snapshot(), []string{"logs-2016/nightly-1/logs-v1-2016-04-06"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("delete queue after reload = %v, want %v", got, want)
+	}
+}
@@ -6,19 +6,21 @@ import (
 	"log"
 	"fmt"
 	"net/http"
-	"os"
+	"strings"
 
 	errors "github.com/go-openapi/errors"
 	runtime "github.com/go-openapi/runtime"
 	middleware "github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/swag"
 
-	elastic "gopkg.in/olivere/elastic.v2"
-
 	"github.com/danisla/esio/models"
 	"github.com/danisla/esio/restapi/operations"
+	"github.com/danisla/esio/restapi/operations/admin"
+	"github.com/danisla/esio/restapi/operations/datasets"
 	"github.com/danisla/esio/restapi/operations/health"
 	"github.com/danisla/esio/restapi/operations/index"
+	"github.com/danisla/esio/restapi/operations/jobs"
+	"github.com/danisla/esio/restapi/operations/settings"
 )
 
 // This file is safe to edit. Once it exists it will not be overwritten
@@ -27,9 +29,30 @@ import (
 
 var myFlags = struct {
 	EsHost string `long:"es-host" description:"Elasticsearch Host [$ES_HOST]"`
-	MaxRestore int `long:"max-restore" description:"Maximum number of indices allowed to restore at once, default is 1 [$MAX_RESTORE]"`
-	IndexResolution string `long:"resolution" description:"Resolution of indices being restored (day, month, year) [$INDEX_RESOLUTION]"`
-	RepoPattern string `long:"repo-pattern" description:"Snapshot repo pattern (repo/snap/index), ex: logs-%Y/logs-%Y-%m-%d/logs-v1-%Y-%m-%d, [$REPO_PATTERN]"`
+	EsUsername string `long:"es-username" description:"Elasticsearch basic auth username, the password is set with ES_PASSWORD or --es-password-file [$ES_USERNAME]"`
+	EsPasswordFile string `long:"es-password-file" description:"File with the Elasticsearch basic auth password [$ES_PASSWORD_FILE]"`
+	EsAPIKeyFile string `long:"es-api-key-file" description:"File with an Elasticsearch API key, encoded or id:api_key, the key can also be set with ES_API_KEY [$ES_API_KEY_FILE]"`
+	EsBearerTokenFile string `long:"es-bearer-token-file" description:"File with a bearer token for Elasticsearch, the token can also be set with ES_BEARER_TOKEN [$ES_BEARER_TOKEN_FILE]"`
+	EsTimeout string `long:"es-timeout" description:"Timeout of requests to Elasticsearch including retries, default is 30s [$ES_TIMEOUT]"`
+	EsRestoreTimeout string `long:"es-restore-timeout" description:"Time a restore has to recover the primary shards of its indices, default is 2h [$ES_RESTORE_TIMEOUT]"`
+	EsRecoveryStallTimeout string `long:"es-recovery-stall-timeout" description:"Time the recovery of a restored index can go without progress before its restore fails, default is 30m, 0 never fails stalled recoveries [$ES_RECOVERY_STALL_TIMEOUT]"`
+	EsCAFile string `long:"es-ca-file" description:"PEM CA bundle used to verify the Elasticsearch certificate [$ES_CA_FILE]"`
+	EsCertFile string `long:"es-cert-file" description:"PEM client certificate for Elasticsearch mTLS [$ES_CERT_FILE]"`
+	EsKeyFile string `long:"es-key-file" description:"PEM client key for Elasticsearch mTLS [$ES_KEY_FILE]"`
+	MaxRestore int `long:"max-restore" description:"Maximum number of snapshot restores allowed to run at once, default is 1, can be changed at runtime with PUT /settings [$MAX_RESTORE]"`
+	IndexResolution string `long:"resolution" description:"Resolution of indices being restored (hour, day, week, month, year) or a duration like 6h [$INDEX_RESOLUTION]"`
+	RepoPattern string `long:"repo-pattern" description:"Snapshot repo pattern (repo/snap/index), snap can be a glob like nightly-* to pick the newest snapshot with the index, ex: logs-%Y/logs-%Y-%m-%d/logs-v1-%Y-%m-%d, [$REPO_PATTERN]"`
+	AcceptPartial bool `long:"accept-partial" description:"Restore indices from the newest PARTIAL snapshot when they have no SUCCESS snapshot [$ACCEPT_PARTIAL]"`
+	StateDir string `long:"state-dir" description:"Directory where the restore and delete queues are persisted across restarts, queues are kept in memory only if not set [$STATE_DIR]"`
+	DefaultTTL string `long:"default-ttl" description:"Default time to keep restored indices before they are deleted, ex: 48h or 7d, indices are kept until deleted if not set [$DEFAULT_TTL]"`
+	DiskWatermark int `long:"disk-watermark" description:"Maximum percent of data node disk used after a restore, restores that would go over are deferred, default is 85, 100 disables the check [$DISK_WATERMARK]"`
+	MaxRestoredBytes int64 `long:"max-restored-bytes" description:"Budget of restored data in bytes, least recently requested restored indices are deleted to make room for new restores, no limit if not set [$MAX_RESTORED_BYTES]"`
+	MaxRestoredIndices int `long:"max-restored-indices" description:"Budget of restored indices, least recently requested restored indices are deleted to make room for new restores, no limit if not set [$MAX_RESTORED_INDICES]"`
+	Timezone string `long:"timezone" description:"IANA timezone index names are computed in, ex: America/Los_Angeles, default is UTC [$TIMEZONE]"`
+	Alias string `long:"alias" description:"Alias attached to restored indices once they are green and detached before they are deleted, ex: restored-logs [$ALIAS]"`
+	SnapshotRefreshInterval string `long:"snapshot-refresh-interval" description:"How often the snapshot catalog is refreshed from the cluster, default is 5m, 0 disables background refreshes [$SNAPSHOT_REFRESH_INTERVAL]"`
+	Datasets string `long:"datasets" description:"YAML or JSON file of named datasets, each with a repo_pattern and optional resolution, timezone and ttl [$DATASETS]"`
+	Config string `long:"config" description:"YAML or JSON config file, flags and env vars override its settings, reloaded on SIGHUP or when the file changes [$CONFIG]"`
 }{}
 
 func configureFlags(api *operations.EsioAPI) {
@@ -42,9 +65,6 @@ func configureFlags(api *operations.EsioAPI) {
 			Options: &myFlags,
 		},
   }
-
-	// Initialize the restore queue
-	initQueues()
 }
 
 func configureAPI(api *operations.EsioAPI) http.Handler {
@@ -61,51 +81,79 @@ func configureAPI(api *operations.EsioAPI) http.Handler {
 
 	api.JSONProducer = runtime.JSONProducer()
 
-	if myFlags.EsHost == "" {
-		if os.Getenv("ES_HOST") != "" {
-			myFlags.EsHost = os.Getenv("ES_HOST")
-		} else {
-			panic("No es-host flag or ES_HOST env provided.")
-		}
+	// Validate the settings and probe the cluster before serving, failures exit with a code per kind of problem.
+	cfg, err := loadConfig()
+	if err != nil {
+		exitOnStartupError(err)
 	}
-
-	if myFlags.IndexResolution == "" {
-		if os.Getenv("INDEX_RESOLUTION") != "" {
-			myFlags.IndexResolution = os.Getenv("INDEX_RESOLUTION")
-		} else {
-			panic("No resolution flag or INDEX_RESOLUTION env provided.")
-		}
+	applyConfig(cfg)
+	if err := validateStartup(cfg); err != nil {
+		exitOnStartupError(err)
+	}
+	if myFlags.Config != "" {
+		log.Println(fmt.Sprintf("Loaded config from: %s", myFlags.Config))
+	}
+	if len(cfg.datasets) > 0 {
+		log.Println(fmt.Sprintf("Loaded %d datasets", len(cfg.datasets)))
 	}
 
-	if myFlags.RepoPattern == "" {
-		if os.Getenv("REPO_PATTERN") != "" {
-			myFlags.RepoPattern = os.Getenv("REPO_PATTERN")
-		} else {
-			panic("No repo-pattern flag or REPO_PATTERN env provided.")
-		}
+	// Initialize the restore and delete queues, replaying any persisted work.
+	if err := initQueues(); err != nil {
+		exitOnStartupError(err)
 	}
 
+	// Reload the config on SIGHUP or when the config file changes.
+	watchConfig()
+
+	// Keep the snapshot catalog fresh.
+	watchSnapshotCatalog()
+
 	api.IndexGetStartEndHandler = index.GetStartEndHandlerFunc(func(params index.GetStartEndParams) middleware.Responder {
  		var msg = ""
 
-		start, end, err := parseTimeRange(params.Start, params.End)
+		// Timezone override
+		var location = currentConfig().location
+		if params.Tz != nil && *params.Tz != "" {
+			loc, err := parseTimezone(*params.Tz)
+			if err != nil {
+				msg := fmt.Sprintf("%s", err)
+				return index.NewGetStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
+			}
+			location = loc
+		}
+
+		start, end, err := parseTimeRange(params.Start, params.End, location)
 		if err != nil {
 			msg := fmt.Sprintf("%s", err)
 			return index.NewGetStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
 		}
 
 		// Index resolution override
-		var indexResolution = myFlags.IndexResolution
+		var indexResolution = currentConfig().Indices.Resolution
 		if params.Resolution != nil && *params.Resolution != "" {
 			indexResolution = *params.Resolution
 		}
 
 		// Repo pattern override
-		var repoPattern = myFlags.RepoPattern
+		var repoPattern = currentConfig().Indices.RepoPattern
 		if params.RepoPattern != nil && *params.RepoPattern != "" {
 			repoPattern = *params.RepoPattern
 		}
 
+		// Rename of the restored indices
+		var renamePattern, renameReplacement string
+		if params.RenamePattern != nil {
+			renamePattern = *params.RenamePattern
+		}
+		if params.RenameReplacement != nil {
+			renameReplacement = *params.RenameReplacement
+		}
+		rename, err := parseRename(renamePattern, renameReplacement)
+		if err != nil {
+			msg = fmt.Sprintf("%s", err)
+			return index.NewGetStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
+		}
+
 		// Look for indices in given range.
 		indices, err := makeIndexListFromRange(start, end, indexResolution, repoPattern)
 		if err != nil {
@@ -113,24 +161,24 @@ func configureAPI(api *operations.EsioAPI) http.Handler {
 			return index.NewGetStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
 		}
 
-		// Iterate through list and validate each index.
-		var allPass = true
-		for _, i := range indices {
-			passed, err := validateSnapshotIndex(i)
-			if err != nil {
-				msg = fmt.Sprintf("Error validating index: %s: %s", i, err)
-				return index.NewGetStartEndRequestRangeNotSatisfiable().WithPayload(&models.Error{Message: &msg})
-			}
-			allPass = allPass && passed
+		// Choose the snapshot each index is restored from.
+		indices, err = resolveSnapshotIndices(params.HTTPRequest.Context(), indices)
+		if err != nil {
+			msg = fmt.Sprintf("Error validating index: %s", err)
+			return index.NewGetStartEndRequestRangeNotSatisfiable().WithPayload(&models.Error{Message: &msg})
 		}
 
 		// Create the IndexStatus data structure
-		indiceStatus, err := makeIndexStatus(indices)
+		indiceStatus, err := makeIndexStatus(params.HTTPRequest.Context(), indices, &rename)
 		if err != nil {
 			msg = fmt.Sprintf("Error comparing online indices with snapshots list: %s", err)
 			return index.NewGetStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
 		}
 
+		// Looking at a range keeps its restored indices around for another TTL and marks them as recently used.
+		leaseStore.Touch(indices)
+		accessLog.Touch(indices)
+
 		// See if all requested indices are Ready
 		var allReady = true
 		var allPending = true
@@ -138,7 +186,7 @@ func configureAPI(api *operations.EsioAPI) http.Handler {
 		for _, indice := range indices {
 			allReady = allReady && stringInList(indiceStatus.Ready, indice)
 			allPending = allPending && stringInList(indiceStatus.Pending, indice)
-			restoringOrPending = restoringOrPending && (stringInList(indiceStatus.Restoring, indice) || stringInList(indiceStatus.Pending, indice) || stringInList(indiceStatus.Deleting, indice))
+			restoringOrPending = restoringOrPending && (stringInList(indiceStatus.Restoring, indice) || stringInList(indiceStatus.Deferred, indice) || stringInList(indiceStatus.Pending, indice) || stringInList(indiceStatus.Deleting, indice))
 		}
 
 		if allReady {
@@ -170,24 +218,83 @@ func configureAPI(api *operations.EsioAPI) http.Handler {
 	api.IndexPostStartEndHandler = index.PostStartEndHandlerFunc(func(params index.PostStartEndParams) middleware.Responder {
 		var msg = ""
 
-		start, end, err := parseTimeRange(params.Start, params.End)
+		// Timezone override
+		var location = currentConfig().location
+		if params.Tz != nil && *params.Tz != "" {
+			loc, err := parseTimezone(*params.Tz)
+			if err != nil {
+				msg := fmt.Sprintf("%s", err)
+				return index.NewPostStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
+			}
+			location = loc
+		}
+
+		start, end, err := parseTimeRange(params.Start, params.End, location)
 		if err != nil {
 			msg := fmt.Sprintf("%s", err)
 			return index.NewPostStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
 		}
 
+		// TTL override
+		var ttl = currentConfig().defaultTTL
+		if params.TTL != nil && *params.TTL != "" {
+			ttl, err = parseTTL(*params.TTL)
+			if err != nil {
+				msg = fmt.Sprintf("%s", err)
+				return index.NewPostStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
+			}
+		}
+
 		// Index resolution override
-		var indexResolution = myFlags.IndexResolution
+		var indexResolution = currentConfig().Indices.Resolution
 		if params.Resolution != nil && *params.Resolution != "" {
 			indexResolution = *params.Resolution
 		}
 
 		// Repo pattern override
-		var repoPattern = myFlags.RepoPattern
+		var repoPattern = currentConfig().Indices.RepoPattern
 		if params.RepoPattern != nil && *params.RepoPattern != "" {
 			repoPattern = *params.RepoPattern
 		}
 
+		// Rename of the restored indices
+		var renamePattern, renameReplacement string
+		if params.RenamePattern != nil {
+			renamePattern = *params.RenamePattern
+		}
+		if params.RenameReplacement != nil {
+			renameReplacement = *params.RenameReplacement
+		}
+		rename, err := parseRename(renamePattern, renameReplacement)
+		if err != nil {
+			msg = fmt.Sprintf("%s", err)
+			return index.NewPostStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
+		}
+
+		// Restore options of the indices
+		var indexSettings, ignoreIndexSettings string
+		if params.IndexSettings != nil {
+			indexSettings = *params.IndexSettings
+		}
+		if params.IgnoreIndexSettings != nil {
+			ignoreIndexSettings = *params.IgnoreIndexSettings
+		}
+		opts, err := parseRestoreOptions(rename, indexSettings, ignoreIndexSettings, params.IncludeAliases, params.IncludeGlobalState)
+		if err != nil {
+			msg = fmt.Sprintf("%s", err)
+			return index.NewPostStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
+		}
+
+		// Alias override
+		opts.Alias = currentConfig().Indices.Alias
+		if params.Alias != nil && *params.Alias != "" {
+			if err := validateAlias(*params.Alias); err != nil {
+				msg = fmt.Sprintf("%s", err)
+				return index.NewPostStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
+			}
+			opts.Alias = *params.Alias
+		}
+
 		// Look for indices in given range.
 		indices, err := makeIndexListFromRange(start, end, indexResolution, repoPattern)
 		if err != nil {
@@ -195,19 +302,27 @@ func configureAPI(api *operations.EsioAPI) http.Handler {
 			return index.NewPostStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
 		}
 
-		// Iterate through list and validate each index.
-		var allPass = true
-		for _, i := range indices {
-			passed, err := validateSnapshotIndex(i)
-			if err != nil {
-				msg = fmt.Sprintf("Error validating index: %s: %s", i, err)
-				return index.NewPostStartEndRequestRangeNotSatisfiable().WithPayload(&models.Error{Message: &msg})
-			}
-			allPass = allPass && passed
+		// Choose the snapshot each index is restored from.
+		indices, err = resolveSnapshotIndices(params.HTTPRequest.Context(), indices)
+		if err != nil {
+			msg = fmt.Sprintf("Error validating index: %s", err)
+			return index.NewPostStartEndRequestRangeNotSatisfiable().WithPayload(&models.Error{Message: &msg})
+		}
+
+		// Indices esio restored under another name, with other index settings or alias have to be deleted
+		// before they are restored again, their restore options are never rewritten.
+		conflicts, err := conflictingIndices(params.HTTPRequest.Context(), indices, opts)
+		if err != nil {
+			msg = fmt.Sprintf("%s", err)
+			return index.NewPostStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
+		}
+		if len(conflicts) > 0 {
+			msg = fmt.Sprintf("Indices in range are already restored with other restore options, delete them first: %s", strings.Join(conflicts, ","))
+			return index.NewPostStartEndConflict().WithPayload(&models.Error{Message: &msg})
 		}
 
 		// Create the IndexStatus data structure
-		indiceStatus, err := makeIndexStatus(indices)
+		indiceStatus, err := makeIndexStatus(params.HTTPRequest.Context(), indices, &rename)
 		if err != nil {
 			msg = fmt.Sprintf("Error comparing online indices with snapshots list: %s", err)
 			return index.NewPostStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
@@ -215,24 +330,36 @@ func configureAPI(api *operations.EsioAPI) http.Handler {
 
 		// See if all requested indices are Ready
 		var allReady = true
-		var restoreStarted = false
+		var toRestore = make([]string, 0)
 		for _, indice := range indices {
 
 			// If index is not ready and is pending, then start restoring it.
 			if !stringInList(indiceStatus.Ready, indice) && stringInList(indiceStatus.Pending, indice) {
 				allReady = false
+				toRestore = append(toRestore, indice)
+			}
+		}
 
-				// Queue for restore
-				restoreQueue.Push(&Node{indice})
+		// Workers restore the queued indices with their options, and recover, evict and delete them under
+		// their restored name.
+		restoreOptionsStore.Set(toRestore, opts)
 
-				log.Println(fmt.Sprintf("Index queued for restore: %s", indice))
+		// Record the job before queueing so workers can report progress on it.
+		job := jobStore.Create(jobTypeRestore, start.Unix(), end.Unix(), indexResolution, repoPattern, indices, toRestore)
 
-				restoreStarted = true
-			}
+		for _, indice := range toRestore {
+			// Queue for restore
+			restoreQueue.Push(&Node{indice})
+
+			log.Println(fmt.Sprintf("Index queued for restore: %s, job: %s", indice, job.ID))
 		}
 
+		// Start or extend the lease of every index in range restored by esio.
+		grantLeases(indices, ttl)
+		accessLog.Touch(indices)
+
 		// Rebuild the indice status
-		newIndiceStatus, err := makeIndexStatus(indices)
+		newIndiceStatus, err := makeIndexStatus(params.HTTPRequest.Context(), indices, &rename)
 		if err != nil {
 			msg = fmt.Sprintf("Error comparing online indices with snapshots list: %s", err)
 			return index.NewPostStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
@@ -240,38 +367,63 @@ func configureAPI(api *operations.EsioAPI) http.Handler {
 
 		// If all indices are online and ready then we are done.
 		if allReady {
-			return index.NewPostStartEndOK().WithPayload(&newIndiceStatus)
+			return index.NewPostStartEndOK().WithLocation(jobLocation(job)).WithPayload(&newIndiceStatus)
 		}
 
-		if restoreStarted {
-			return index.NewPostStartEndAccepted().WithPayload(&newIndiceStatus)
+		if len(toRestore) > 0 {
+			return index.NewPostStartEndAccepted().WithLocation(jobLocation(job)).WithPayload(&newIndiceStatus)
 		}
 
 		// If we made it here then some of the indices are restoring
-		return index.NewPostStartEndPartialContent().WithPayload(&newIndiceStatus)
+		return index.NewPostStartEndPartialContent().WithLocation(jobLocation(job)).WithPayload(&newIndiceStatus)
 	})
 
 	api.IndexDeleteStartEndHandler = index.DeleteStartEndHandlerFunc(func(params index.DeleteStartEndParams) middleware.Responder {
 		var msg = ""
 
-		start, end, err := parseTimeRange(params.Start, params.End)
+		// Timezone override
+		var location = currentConfig().location
+		if params.Tz != nil && *params.Tz != "" {
+			loc, err := parseTimezone(*params.Tz)
+			if err != nil {
+				msg := fmt.Sprintf("%s", err)
+				return index.NewDeleteStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
+			}
+			location = loc
+		}
+
+		start, end, err := parseTimeRange(params.Start, params.End, location)
 		if err != nil {
 			msg := fmt.Sprintf("%s", err)
 			return index.NewDeleteStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
 		}
 
 		// Index resolution override
-		var indexResolution = myFlags.IndexResolution
+		var indexResolution = currentConfig().Indices.Resolution
 		if params.Resolution != nil && *params.Resolution != "" {
 			indexResolution = *params.Resolution
 		}
 
 		// Repo pattern override
-		var repoPattern = myFlags.RepoPattern
+		var repoPattern = currentConfig().Indices.RepoPattern
 		if params.RepoPattern != nil && *params.RepoPattern != "" {
 			repoPattern = *params.RepoPattern
 		}
 
+		// Rename of the restored indices
+		var renamePattern, renameReplacement string
+		if params.RenamePattern != nil {
+			renamePattern = *params.RenamePattern
+		}
+		if params.RenameReplacement != nil {
+			renameReplacement = *params.RenameReplacement
+		}
+		rename, err := parseRename(renamePattern, renameReplacement)
+		if err != nil {
+			msg = fmt.Sprintf("%s", err)
+			return index.NewDeleteStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
+		}
+
 		// Look for indices in given range.
 		indices, err := makeIndexListFromRange(start, end, indexResolution, repoPattern)
 		if err != nil {
@@ -279,32 +431,55 @@ func configureAPI(api *operations.EsioAPI) http.Handler {
 			return index.NewDeleteStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
 		}
 
-		// Not allowed to delete restoring indices
+		// Indices are tracked by the path of the snapshot they were restored from, indices whose snapshot
+		// can't be chosen anymore are only matched by name on the cluster.
+		for n, i := range indices {
+			if r, err := resolveSnapshotIndex(params.HTTPRequest.Context(), i); err == nil {
+				indices[n] = r
+			}
+		}
+
+		// Not allowed to delete restoring indices, queued or taken off the queue by a restore worker
+		restoring := jobStore.PendingIndices(jobTypeRestore)
 		for _, indice := range indices {
-			if restoreQueue.Contains(indice) {
+			if restoreQueue.Contains(indice) || stringInList(restoring, indice) {
 				msg = fmt.Sprintf("Index in range is currently being restored and cannot be deleted at this time: %s", indice)
 				return index.NewDeleteStartEndRequestRangeNotSatisfiable().WithPayload(&models.Error{Message: &msg})
 			}
 		}
 
-		deleteActive, err := deleteIndices(indices)
+		// Indices are deleted under the name they were restored as, a rename only selects the indices
+		// restored with it.
+		var filter *Rename
+		if rename.Pattern != "" {
+			filter = &rename
+		}
+		toDelete, err := deletableIndices(params.HTTPRequest.Context(), indices, filter)
 		if err != nil {
 			msg = fmt.Sprintf("Error deleting index: %s", err)
 			return index.NewDeleteStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
 		}
 
+		// Record the job before queueing so workers can report progress on it.
+		job := jobStore.Create(jobTypeDelete, start.Unix(), end.Unix(), indexResolution, repoPattern, indices, toDelete)
+
+		for _, indice := range toDelete {
+			deleteQueue.Push(&Node{indice})
+		}
+		leaseStore.Release(indices)
+
 		// Create the IndexStatus data structure
-		indiceStatus, err := makeIndexStatus(indices)
+		indiceStatus, err := makeIndexStatus(params.HTTPRequest.Context(), indices, nil)
 		if err != nil {
 			msg = fmt.Sprintf("Error comparing online indices with snapshots list: %s", err)
 			return index.NewPostStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
 		}
 
-		if deleteActive {
-			return index.NewDeleteStartEndAccepted().WithPayload(&indiceStatus)
+		if len(toDelete) > 0 {
+			return index.NewDeleteStartEndAccepted().WithLocation(jobLocation(job)).WithPayload(&indiceStatus)
 		}
 
-		return index.NewDeleteStartEndOK().WithPayload(&indiceStatus)
+		return index.NewDeleteStartEndOK().WithLocation(jobLocation(job)).WithPayload(&indiceStatus)
 
 	})
 
@@ -312,31 +487,182 @@ func configureAPI(api *operations.EsioAPI) http.Handler {
 		var status = "OK"
 		var message = "Healthy"
 
-		client, err := elastic.NewClient(
-			elastic.SetURL(myFlags.EsHost),
-			elastic.SetHealthcheck(false),
-			elastic.SetSniff(false))
-		if err != nil {
-		  status = "ERROR"
-			message = fmt.Sprintf("%s", err)
-		}
-
 		// Get cluster health
-		res, err := client.ClusterHealth().Do()
+		clusterStatus, err := backend.ClusterHealth(params.HTTPRequest.Context())
 		if err != nil {
 			status = "ERROR"
 			message = fmt.Sprintf("%s", err)
-		}
-		if res == nil {
+		} else if clusterStatus == "" {
 			status = "ERROR"
 			message = "Error connecting to cluster"
 		}
 
+		payload := &models.Healthz{
+			Status:         &status,
+			Message:        &message,
+			MaxRestore:     int64(restorePool.Max()),
+			ActiveRestores: int64(restorePool.Active()),
+		}
+
 		if status != "ERROR" {
-			return health.NewGetHealthzOK().WithPayload(&models.Healthz{Status: &status, Message: &message})
+			return health.NewGetHealthzOK().WithPayload(payload)
 		} else {
-			return health.NewGetHealthzDefault(503).WithPayload(&models.Healthz{Status: &status, Message: &message})
+			return health.NewGetHealthzDefault(503).WithPayload(payload)
+		}
+	})
+
+	api.JobsGetJobsHandler = jobs.GetJobsHandlerFunc(func(params jobs.GetJobsParams) middleware.Responder {
+		return jobs.NewGetJobsOK().WithPayload(jobStore.List())
+	})
+
+	api.JobsGetJobsIDHandler = jobs.GetJobsIDHandlerFunc(func(params jobs.GetJobsIDParams) middleware.Responder {
+		job, ok := jobStore.Get(params.ID)
+		if !ok {
+			msg := fmt.Sprintf("Job not found: %s", params.ID)
+			return jobs.NewGetJobsIDNotFound().WithPayload(&models.Error{Message: &msg})
+		}
+		return jobs.NewGetJobsIDOK().WithPayload(job)
+	})
+
+	api.JobsDeleteJobsIDHandler = jobs.DeleteJobsIDHandlerFunc(func(params jobs.DeleteJobsIDParams) middleware.Responder {
+		job, err := cancelJob(params.ID)
+		if err != nil {
+			msg := fmt.Sprintf("%s", err)
+			if e, ok := err.(errors.Error); ok && e.Code() == 409 {
+				return jobs.NewDeleteJobsIDConflict().WithPayload(&models.Error{Message: &msg})
+			}
+			return jobs.NewDeleteJobsIDNotFound().WithPayload(&models.Error{Message: &msg})
+		}
+
+		log.Println(fmt.Sprintf("Cancelled %s job: %s", job.Type, job.ID))
+
+		return jobs.NewDeleteJobsIDOK().WithPayload(job)
+	})
+
+	api.SettingsGetSettingsHandler = settings.GetSettingsHandlerFunc(func(params settings.GetSettingsParams) middleware.Responder {
+		maxRestore := int64(restorePool.Max())
+		return settings.NewGetSettingsOK().WithPayload(&models.Settings{MaxRestore: &maxRestore})
+	})
+
+	api.SettingsPutSettingsHandler = settings.PutSettingsHandlerFunc(func(params settings.PutSettingsParams) middleware.Responder {
+		restorePool.SetMax(int(*params.Settings.MaxRestore))
+		log.Println(fmt.Sprintf("Maximum concurrent restores set to: %d", restorePool.Max()))
+
+		maxRestore := int64(restorePool.Max())
+		return settings.NewPutSettingsOK().WithPayload(&models.Settings{MaxRestore: &maxRestore})
+	})
+
+	api.DatasetsGetDatasetsHandler = datasets.GetDatasetsHandlerFunc(func(params datasets.GetDatasetsParams) middleware.Responder {
+		return datasets.NewGetDatasetsOK().WithPayload(datasetRegistry.List())
+	})
+
+	// Dataset routes resolve the dataset and are then handled like a request to /{start}/{end} with its overrides.
+	api.DatasetsGetDatasetsNameStartEndHandler = datasets.GetDatasetsNameStartEndHandlerFunc(func(params datasets.GetDatasetsNameStartEndParams) middleware.Responder {
+		dataset, ok := datasetRegistry.Get(params.Name)
+		if !ok {
+			msg := fmt.Sprintf("Dataset not found: %s", params.Name)
+			return datasets.NewGetDatasetsNameStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
+		}
+
+		return api.IndexGetStartEndHandler.Handle(index.GetStartEndParams{
+			HTTPRequest:       params.HTTPRequest,
+			Start:             params.Start,
+			End:               params.End,
+			RepoPattern:       &dataset.RepoPattern,
+			Resolution:        &dataset.Resolution,
+			Tz:                &dataset.Timezone,
+			RenamePattern:     &dataset.RenamePattern,
+			RenameReplacement: &dataset.RenameReplacement,
+		})
+	})
+
+	api.DatasetsPostDatasetsNameStartEndHandler = datasets.PostDatasetsNameStartEndHandlerFunc(func(params datasets.PostDatasetsNameStartEndParams) middleware.Responder {
+		dataset, ok := datasetRegistry.Get(params.Name)
+		if !ok {
+			msg := fmt.Sprintf("Dataset not found: %s", params.Name)
+			return datasets.NewPostDatasetsNameStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
+		}
+
+		// TTL override
+		var ttl = dataset.TTL
+		if params.TTL != nil && *params.TTL != "" {
+			ttl = *params.TTL
+		}
+
+		// Restore option overrides, the index settings are passed on in the format of the request params.
+		indexSettings := params.IndexSettings
+		if indexSettings == nil && len(dataset.IndexSettings) > 0 {
+			data, _ := json.Marshal(dataset.IndexSettings)
+			raw := string(data)
+			indexSettings = &raw
+		}
+		ignoreIndexSettings := params.IgnoreIndexSettings
+		if ignoreIndexSettings == nil {
+			raw := strings.Join(dataset.IgnoreIndexSettings, ",")
+			ignoreIndexSettings = &raw
+		}
+		includeAliases := params.IncludeAliases
+		if includeAliases == nil {
+			includeAliases = dataset.IncludeAliases
+		}
+		includeGlobalState := params.IncludeGlobalState
+		if includeGlobalState == nil {
+			includeGlobalState = dataset.IncludeGlobalState
+		}
+
+		return api.IndexPostStartEndHandler.Handle(index.PostStartEndParams{
+			HTTPRequest:         params.HTTPRequest,
+			Start:               params.Start,
+			End:                 params.End,
+			RepoPattern:         &dataset.RepoPattern,
+			Resolution:          &dataset.Resolution,
+			Tz:                  &dataset.Timezone,
+			RenamePattern:       &dataset.RenamePattern,
+			RenameReplacement:   &dataset.RenameReplacement,
+			IndexSettings:       indexSettings,
+			IgnoreIndexSettings: ignoreIndexSettings,
+			IncludeAliases:      includeAliases,
+			IncludeGlobalState:  includeGlobalState,
+			Alias:               &dataset.Alias,
+			TTL:                 &ttl,
+		})
+	})
+
+	api.DatasetsDeleteDatasetsNameStartEndHandler = datasets.DeleteDatasetsNameStartEndHandlerFunc(func(params datasets.DeleteDatasetsNameStartEndParams) middleware.Responder {
+		dataset, ok := datasetRegistry.Get(params.Name)
+		if !ok {
+			msg := fmt.Sprintf("Dataset not found: %s", params.Name)
+			return datasets.NewDeleteDatasetsNameStartEndBadRequest().WithPayload(&models.Error{Message: &msg})
+		}
+
+		return api.IndexDeleteStartEndHandler.Handle(index.DeleteStartEndParams{
+			HTTPRequest:       params.HTTPRequest,
+			Start:             params.Start,
+			End:               params.End,
+			RepoPattern:       &dataset.RepoPattern,
+			Resolution:        &dataset.Resolution,
+			Tz:                &dataset.Timezone,
+			RenamePattern:     &dataset.RenamePattern,
+			RenameReplacement: &dataset.RenameReplacement,
+		})
+	})
+
+	api.AdminPostAdminSnapshotsRefreshHandler = admin.PostAdminSnapshotsRefreshHandlerFunc(func(params admin.PostAdminSnapshotsRefreshParams) middleware.Responder {
+		var repo string
+		if params.Repo != nil {
+			repo = *params.Repo
 		}
+
+		if err := snapshotCatalog.Refresh(params.HTTPRequest.Context(), repo, 0); err != nil {
+			code := 500
+			if e, ok := err.(errors.Error); ok {
+				code = int(e.Code())
+			}
+			msg := fmt.Sprintf("Error refreshing snapshot catalog: %s", err)
+			return admin.NewPostAdminSnapshotsRefreshDefault(code).WithPayload(&models.Error{Message: &msg})
+		}
+
+		return admin.NewPostAdminSnapshotsRefreshOK().WithPayload(snapshotCatalog.List())
 	})
 
 	api.ServerShutdown = func() {}
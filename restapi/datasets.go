@@ -0,0 +1,184 @@
+package restapi
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/danisla/esio/models"
+)
+
+// DatasetConfig is a named dataset as defined in the datasets file.
+type DatasetConfig struct {
+	RepoPattern string `yaml:"repo_pattern"`
+	Resolution  string `yaml:"resolution"`
+	Timezone    string `yaml:"timezone"`
+	TTL         string `yaml:"ttl"`
+	// Restored indices are renamed with the regex and replacement so they don't clash with live indices.
+	RenamePattern     string `yaml:"rename_pattern"`
+	RenameReplacement string `yaml:"rename_replacement"`
+	// Restored indices get the index settings, nested maps are flattened into full setting names.
+	IndexSettings       map[string]interface{} `yaml:"index_settings"`
+	IgnoreIndexSettings []string               `yaml:"ignore_index_settings"`
+	IncludeAliases      *bool                  `yaml:"include_aliases"`
+	IncludeGlobalState  *bool                  `yaml:"include_global_state"`
+	// Alias attached to restored indices of the dataset, overrides the server alias.
+	Alias string `yaml:"alias"`
+}
+
+// DatasetsFile is the format of the datasets file, YAML or JSON, ex:
+//
+//   datasets:
+//     nginx:
+//       repo_pattern: nginx-%Y/nginx-%Y-%m-%d/nginx-v1-%Y-%m-%d
+//       resolution: day
+//       timezone: America/Los_Angeles
+//       ttl: 48h
+//       rename_pattern: (.+)
+//       rename_replacement: restored-$1
+//       index_settings:
+//         number_of_replicas: 0
+//         refresh_interval: 30s
+//       include_aliases: false
+//       alias: restored-nginx
+type DatasetsFile struct {
+	Datasets map[string]DatasetConfig `yaml:"datasets"`
+}
+
+// DatasetRegistry holds the named datasets clients can request by name instead of passing a repo pattern.
+type DatasetRegistry struct {
+	datasets map[string]*models.Dataset
+	mu       sync.RWMutex
+}
+
+var datasetRegistry = &DatasetRegistry{datasets: make(map[string]*models.Dataset)}
+
+// Get returns a copy of the dataset with the given name.
+func (r *DatasetRegistry) Get(name string) (*models.Dataset, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	dataset, ok := r.datasets[name]
+	if !ok {
+		return nil, false
+	}
+	c := *dataset
+	return &c, true
+}
+
+// List returns copies of all datasets sorted by name.
+func (r *DatasetRegistry) List() []*models.Dataset {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.datasets))
+	for name := range r.datasets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := make([]*models.Dataset, 0, len(names))
+	for _, name := range names {
+		c := *r.datasets[name]
+		list = append(list, &c)
+	}
+	return list
+}
+
+// Set replaces all datasets.
+func (r *DatasetRegistry) Set(datasets map[string]*models.Dataset) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.datasets = datasets
+}
+
+// Loads and validates the datasets in the given file. Every invalid dataset is reported in the error.
+func loadDatasets(path string) (map[string]*models.Dataset, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading datasets file '%s': %s", path, err)
+	}
+
+	var file DatasetsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("Error decoding datasets file '%s': %s", path, err)
+	}
+
+	datasets := make(map[string]*models.Dataset)
+	problems := make([]string, 0)
+	for name, config := range file.Datasets {
+		dataset, err := makeDataset(name, config)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		datasets[name] = dataset
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("Invalid datasets in '%s': %s", path, strings.Join(problems, "; "))
+	}
+
+	return datasets, nil
+}
+
+// Validates a dataset from the datasets file.
+func makeDataset(name string, config DatasetConfig) (*models.Dataset, error) {
+	if config.RepoPattern == "" {
+		return nil, fmt.Errorf("dataset '%s': repo_pattern is required", name)
+	}
+	if err := validateRepoPattern(config.RepoPattern); err != nil {
+		return nil, fmt.Errorf("dataset '%s': %s", name, err)
+	}
+	if config.Resolution != "" {
+		if _, err := parseResolution(config.Resolution); err != nil {
+			return nil, fmt.Errorf("dataset '%s': %s", name, err)
+		}
+	}
+	if config.Timezone != "" {
+		if _, err := parseTimezone(config.Timezone); err != nil {
+			return nil, fmt.Errorf("dataset '%s': %s", name, err)
+		}
+	}
+	if config.TTL != "" {
+		if _, err := parseTTL(config.TTL); err != nil {
+			return nil, fmt.Errorf("dataset '%s': %s", name, err)
+		}
+	}
+
+	if _, err := parseRename(config.RenamePattern, config.RenameReplacement); err != nil {
+		return nil, fmt.Errorf("dataset '%s': %s", name, err)
+	}
+	indexSettings, err := makeIndexSettings(config.IndexSettings)
+	if err != nil {
+		return nil, fmt.Errorf("dataset '%s': %s", name, err)
+	}
+	ignoreIndexSettings, err := makeIgnoreIndexSettings(config.IgnoreIndexSettings)
+	if err != nil {
+		return nil, fmt.Errorf("dataset '%s': %s", name, err)
+	}
+	if err := validateAlias(config.Alias); err != nil {
+		return nil, fmt.Errorf("dataset '%s': %s", name, err)
+	}
+
+	return &models.Dataset{
+		Name:                name,
+		RepoPattern:         config.RepoPattern,
+		Resolution:          config.Resolution,
+		Timezone:            config.Timezone,
+		TTL:                 config.TTL,
+		RenamePattern:       config.RenamePattern,
+		RenameReplacement:   config.RenameReplacement,
+		IndexSettings:       indexSettings,
+		IgnoreIndexSettings: ignoreIndexSettings,
+		IncludeAliases:      config.IncludeAliases,
+		IncludeGlobalState:  config.IncludeGlobalState,
+		Alias:               config.Alias,
+	}, nil
+}
@@ -0,0 +1,189 @@
+package restapi
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+)
+
+// How long a deferred restore waits before its disk usage is checked again.
+const deferInterval = 60 * time.Second
+
+type SnapshotStatusResponse struct {
+	Snapshots []SnapshotStatus `json:"snapshots"`
+}
+
+type SnapshotStatus struct {
+	Snapshot string                         `json:"snapshot"`
+	Indices  map[string]SnapshotIndexStatus `json:"indices"`
+}
+
+type SnapshotIndexStatus struct {
+	Stats SnapshotStats `json:"stats"`
+}
+
+// SnapshotStats holds the size of a snapshotted index, ES 5 and later report it under total.
+type SnapshotStats struct {
+	TotalSizeInBytes int64 `json:"total_size_in_bytes"`
+	Total            struct {
+		SizeInBytes int64 `json:"size_in_bytes"`
+	} `json:"total"`
+}
+
+// Size returns the size of the index in bytes.
+func (s SnapshotStats) Size() int64 {
+	if s.Total.SizeInBytes > 0 {
+		return s.Total.SizeInBytes
+	}
+	return s.TotalSizeInBytes
+}
+
+type CatAllocation struct {
+	Node      string `json:"node"`
+	DiskUsed  string `json:"disk.used"`
+	DiskAvail string `json:"disk.avail"`
+	DiskTotal string `json:"disk.total"`
+}
+
+type deferral struct {
+	reason  string
+	retryAt time.Time
+}
+
+// DiskGuard admits a restore only if the cluster disk usage after the restore stays under the watermark.
+// Bytes of admitted restores are reserved until the restore finishes since ES only reports them as used
+// once the shards have been copied. Restores that don't fit are deferred and checked again later.
+type DiskGuard struct {
+	watermark int
+	reserved  int64
+	deferred  map[string]deferral
+	mu        sync.Mutex
+}
+
+var diskGuard *DiskGuard
+
+// NewDiskGuard returns a guard for the given watermark, a percent of the total data node disk.
+// A watermark of 100 or more disables the check.
+func NewDiskGuard(watermark int) *DiskGuard {
+	return &DiskGuard{watermark: watermark, deferred: make(map[string]deferral)}
+}
+
+// Admit checks the restore of the given repo/snap/index paths from snapshot against the watermark.
+// It returns the number of bytes reserved for the restore, to be passed to Release once it is done.
+// A deferred restore returns admitted false, an error is returned if the restore can never fit.
+func (g *DiskGuard) Admit(snapshot string, values []string) (admitted bool, reserved int64, err error) {
+	if g.Watermark() >= 100 {
+		return true, 0, nil
+	}
+
+	sizes, err := backend.SnapshotIndexSizes(context.Background(), snapshot)
+	if err != nil {
+		g.Defer(values, fmt.Sprintf("Could not get snapshot index sizes: %s", err))
+		return false, 0, nil
+	}
+
+	total, avail, err := backend.DiskUsage(context.Background())
+	if err != nil {
+		g.Defer(values, fmt.Sprintf("Could not get cluster disk usage: %s", err))
+		return false, 0, nil
+	}
+
+	var need int64
+	for _, value := range values {
+		need += sizes[path.Base(value)]
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	limit := total * int64(g.watermark) / 100
+	if need > limit {
+		for _, value := range values {
+			delete(g.deferred, value)
+		}
+		return false, 0, fmt.Errorf("restore of %d bytes is larger than the %d%% disk watermark of %d bytes", need, g.watermark, limit)
+	}
+
+	used := total - avail + g.reserved
+	if used+need > limit {
+		reason := fmt.Sprintf("Restore of %d bytes would raise disk usage to %d%%, above the %d%% watermark", need, (used+need)*100/total, g.watermark)
+		g.postpone(values, reason)
+		return false, 0, nil
+	}
+
+	g.reserved += need
+	for _, value := range values {
+		delete(g.deferred, value)
+	}
+	return true, need, nil
+}
+
+// Watermark returns the current watermark.
+func (g *DiskGuard) Watermark() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.watermark
+}
+
+// SetWatermark changes the watermark, restores already admitted are not checked again.
+func (g *DiskGuard) SetWatermark(watermark int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.watermark = watermark
+}
+
+// Release returns bytes reserved by Admit.
+func (g *DiskGuard) Release(reserved int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.reserved -= reserved
+}
+
+// Defer records why the given indices can't be restored yet.
+func (g *DiskGuard) Defer(values []string, reason string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.postpone(values, reason)
+}
+
+// Deferred returns the reason the index was deferred, if it was.
+func (g *DiskGuard) Deferred(value string) (string, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	d, ok := g.deferred[value]
+	return d.reason, ok
+}
+
+// Ready returns false while a deferred index is waiting for its next check.
+func (g *DiskGuard) Ready(value string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	d, ok := g.deferred[value]
+	return !ok || time.Now().After(d.retryAt)
+}
+
+// Forget drops the deferral of the given indices.
+func (g *DiskGuard) Forget(values []string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, value := range values {
+		delete(g.deferred, value)
+	}
+}
+
+// postpone records the deferral of the given indices, the caller must hold g.mu.
+func (g *DiskGuard) postpone(values []string, reason string) {
+	retryAt := time.Now().Add(deferInterval)
+	for _, value := range values {
+		g.deferred[value] = deferral{reason: reason, retryAt: retryAt}
+	}
+}
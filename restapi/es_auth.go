@@ -0,0 +1,126 @@
+package restapi
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Idle connections kept open to the cluster, enough for the restore and delete workers and the handlers.
+const maxIdleConnsPerHost = 16
+
+// TLSConfig is the CA bundle and client certificate used to connect to the cluster.
+type TLSConfig struct {
+	CAFile             string `yaml:"ca_file"`
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+// Returns the Authorization header for the credentials of the config, empty if there are none.
+// Secrets are read from their files so they stay out of the process args.
+func (c *ElasticsearchConfig) authorization() (string, error) {
+	password, err := readSecret(c.Password, c.PasswordFile)
+	if err != nil {
+		return "", fmt.Errorf("invalid elasticsearch password: %s", err)
+	}
+	apiKey, err := readSecret(c.APIKey, c.APIKeyFile)
+	if err != nil {
+		return "", fmt.Errorf("invalid elasticsearch api key: %s", err)
+	}
+	bearerToken, err := readSecret(c.BearerToken, c.BearerTokenFile)
+	if err != nil {
+		return "", fmt.Errorf("invalid elasticsearch bearer token: %s", err)
+	}
+
+	methods := 0
+	for _, set := range []bool{c.Username != "" || password != "", apiKey != "", bearerToken != ""} {
+		if set {
+			methods++
+		}
+	}
+	if methods > 1 {
+		return "", fmt.Errorf("only one of elasticsearch username and password, api key or bearer token can be set")
+	}
+
+	switch {
+	case c.Username != "" || password != "":
+		if c.Username == "" || password == "" {
+			return "", fmt.Errorf("elasticsearch basic auth needs both a username and a password")
+		}
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(c.Username+":"+password)), nil
+	case apiKey != "":
+		// Keys can be given as id:api_key or already encoded as returned by the create API key API.
+		if strings.Contains(apiKey, ":") {
+			apiKey = base64.StdEncoding.EncodeToString([]byte(apiKey))
+		}
+		return "ApiKey " + apiKey, nil
+	case bearerToken != "":
+		return "Bearer " + bearerToken, nil
+	}
+	return "", nil
+}
+
+// Returns the transport for the TLS settings of the config.
+func (c *ElasticsearchConfig) transport() (*http.Transport, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.TLS.InsecureSkipVerify}
+
+	if c.TLS.CAFile != "" {
+		pem, err := ioutil.ReadFile(c.TLS.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading elasticsearch CA file '%s': %s", c.TLS.CAFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in elasticsearch CA file '%s'", c.TLS.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.TLS.CertFile != "" || c.TLS.KeyFile != "" {
+		if c.TLS.CertFile == "" || c.TLS.KeyFile == "" {
+			return nil, fmt.Errorf("elasticsearch client certificates need both a cert file and a key file")
+		}
+		cert, err := tls.LoadX509KeyPair(c.TLS.CertFile, c.TLS.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading elasticsearch client certificate '%s': %s", c.TLS.CertFile, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	// Same dial, TLS handshake and idle timeouts as http.DefaultTransport.
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   maxIdleConnsPerHost,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       tlsConfig,
+	}, nil
+}
+
+// Returns the secret value, or the contents of its file without the trailing newline.
+func readSecret(value string, file string) (string, error) {
+	if value != "" && file != "" {
+		return "", fmt.Errorf("a value and a file can't both be set")
+	}
+	if file == "" {
+		return value, nil
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("error reading secret file '%s': %s", file, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
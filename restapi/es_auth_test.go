@@ -0,0 +1,206 @@
+package restapi
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeTestFile(t *testing.T, dir string, name string, data []byte) string {
+	file := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(file, data, 0600); err != nil {
+		t.Fatalf("Error writing %s: %s", name, err)
+	}
+	return file
+}
+
+// Every request to the cluster carries the Authorization header of the configured credentials.
+func TestAuthorizationHeader(t *testing.T) {
+	defer setConfig(currentConfig())
+
+	dir, err := ioutil.TempDir("", "esio-auth")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	passwordFile := writeTestFile(t, dir, "password", []byte("changeme\n"))
+	apiKeyFile := writeTestFile(t, dir, "api-key", []byte("VuaCfGcBCdbkQm-e5aOx:ui2lp2axTNmsyakw9tvNnw\n"))
+
+	var header string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("Authorization")
+		w.Write([]byte(`{"status": "green"}`))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name string
+		es   ElasticsearchConfig
+		want string
+	}{
+		{"none", ElasticsearchConfig{}, ""},
+		{"basic", ElasticsearchConfig{Username: "elastic", Password: "changeme"}, "Basic ZWxhc3RpYzpjaGFuZ2VtZQ=="},
+		{"basic with password file", ElasticsearchConfig{Username: "elastic", PasswordFile: passwordFile}, "Basic ZWxhc3RpYzpjaGFuZ2VtZQ=="},
+		{"encoded api key", ElasticsearchConfig{APIKey: "VnVhQ2ZHY0JDZGJrUW0tZTVhT3g6dWkybHAyYXhUTm1zeWFrdzl0dk5udw=="}, "ApiKey VnVhQ2ZHY0JDZGJrUW0tZTVhT3g6dWkybHAyYXhUTm1zeWFrdzl0dk5udw=="},
+		{"id:key api key", ElasticsearchConfig{APIKey: "VuaCfGcBCdbkQm-e5aOx:ui2lp2axTNmsyakw9tvNnw"}, "ApiKey VnVhQ2ZHY0JDZGJrUW0tZTVhT3g6dWkybHAyYXhUTm1zeWFrdzl0dk5udw=="},
+		{"api key file", ElasticsearchConfig{APIKeyFile: apiKeyFile}, "ApiKey VnVhQ2ZHY0JDZGJrUW0tZTVhT3g6dWkybHAyYXhUTm1zeWFrdzl0dk5udw=="},
+		{"bearer token", ElasticsearchConfig{BearerToken: "dGhpcyBpcyBhIHRva2Vu"}, "Bearer dGhpcyBpcyBhIHRva2Vu"},
+	}
+	for _, tt := range tests {
+		authorization, err := tt.es.authorization()
+		if err != nil {
+			t.Errorf("%s: authorization() error: %s", tt.name, err)
+			continue
+		}
+		tt.es.Host = srv.URL
+		setConfig(&Config{Elasticsearch: tt.es, authorization: authorization, client: srv.Client(), timeout: 5 * time.Second})
+
+		header = "unset"
+		if _, err := NewHTTPBackend().ClusterHealth(context.Background()); err != nil {
+			t.Errorf("%s: ClusterHealth() error: %s", tt.name, err)
+			continue
+		}
+		if header != tt.want {
+			t.Errorf("%s: Authorization header = %q, want %q", tt.name, header, tt.want)
+		}
+	}
+
+	invalid := []struct {
+		name string
+		es   ElasticsearchConfig
+	}{
+		{"username without password", ElasticsearchConfig{Username: "elastic"}},
+		{"password without username", ElasticsearchConfig{Password: "changeme"}},
+		{"basic and api key", ElasticsearchConfig{Username: "elastic", Password: "changeme", APIKey: "key"}},
+		{"api key and bearer token", ElasticsearchConfig{APIKey: "key", BearerToken: "token"}},
+		{"password and password file", ElasticsearchConfig{Username: "elastic", Password: "changeme", PasswordFile: passwordFile}},
+		{"missing api key file", ElasticsearchConfig{APIKeyFile: filepath.Join(dir, "missing")}},
+	}
+	for _, tt := range invalid {
+		if _, err := tt.es.authorization(); err == nil {
+			t.Errorf("%s: authorization() returned no error", tt.name)
+		}
+	}
+}
+
+// Returns the PEM certificate and key of a new self signed certificate for the given usage.
+func newTestCertificate(t *testing.T, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Error generating key: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "esio"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{usage},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Error creating certificate: %s", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Error encoding key: %s", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// The transport trusts the cluster through the CA file and presents the client certificate for mTLS.
+func TestTransportTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "esio-tls")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	clientCert, clientKey := newTestCertificate(t, x509.ExtKeyUsageClientAuth)
+	certFile := writeTestFile(t, dir, "client.crt", clientCert)
+	keyFile := writeTestFile(t, dir, "client.key", clientKey)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM(clientCert)
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "green"}`))
+	})
+	srv := httptest.NewTLSServer(ok)
+	defer srv.Close()
+	mtls := httptest.NewUnstartedServer(ok)
+	mtls.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	mtls.StartTLS()
+	defer mtls.Close()
+
+	caFile := writeTestFile(t, dir, "ca.crt", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}))
+	mtlsCAFile := writeTestFile(t, dir, "mtls-ca.crt", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: mtls.Certificate().Raw}))
+	otherCA, _ := newTestCertificate(t, x509.ExtKeyUsageServerAuth)
+	otherCAFile := writeTestFile(t, dir, "other-ca.crt", otherCA)
+
+	tests := []struct {
+		name string
+		url  string
+		tls  TLSConfig
+		ok   bool
+	}{
+		{"CA file", srv.URL, TLSConfig{CAFile: caFile}, true},
+		{"no CA file", srv.URL, TLSConfig{}, false},
+		{"wrong CA file", srv.URL, TLSConfig{CAFile: otherCAFile}, false},
+		{"insecure", srv.URL, TLSConfig{InsecureSkipVerify: true}, true},
+		{"client certificate", mtls.URL, TLSConfig{CAFile: mtlsCAFile, CertFile: certFile, KeyFile: keyFile}, true},
+		{"no client certificate", mtls.URL, TLSConfig{CAFile: mtlsCAFile}, false},
+	}
+	for _, tt := range tests {
+		es := ElasticsearchConfig{TLS: tt.tls}
+		transport, err := es.transport()
+		if err != nil {
+			t.Errorf("%s: transport() error: %s", tt.name, err)
+			continue
+		}
+		client := &http.Client{Transport: transport, Timeout: 5 * time.Second}
+		resp, err := client.Get(tt.url)
+		if err == nil {
+			resp.Body.Close()
+		}
+		if tt.ok && err != nil {
+			t.Errorf("%s: request error: %s", tt.name, err)
+		}
+		if !tt.ok && err == nil {
+			t.Errorf("%s: request succeeded, want a TLS error", tt.name)
+		}
+		transport.CloseIdleConnections()
+	}
+
+	_, otherKey := newTestCertificate(t, x509.ExtKeyUsageClientAuth)
+	invalid := []struct {
+		name string
+		tls  TLSConfig
+	}{
+		{"cert without key", TLSConfig{CertFile: certFile}},
+		{"key without cert", TLSConfig{KeyFile: keyFile}},
+		{"missing CA file", TLSConfig{CAFile: filepath.Join(dir, "missing.crt")}},
+		{"CA file without certificates", TLSConfig{CAFile: keyFile}},
+		{"key of another certificate", TLSConfig{CertFile: certFile, KeyFile: writeTestFile(t, dir, "other.key", otherKey)}},
+	}
+	for _, tt := range invalid {
+		es := ElasticsearchConfig{TLS: tt.tls}
+		if _, err := es.transport(); err == nil {
+			t.Errorf("%s: transport() returned no error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package restapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	errors "github.com/go-openapi/errors"
+)
+
+const (
+	distributionElasticsearch = "elasticsearch"
+	distributionOpenSearch    = "opensearch"
+)
+
+type ClusterInfoResponse struct {
+	Version struct {
+		Number       string `json:"number"`
+		Distribution string `json:"distribution"`
+	} `json:"version"`
+}
+
+// ClusterVersion is the distribution and version of the cluster as reported by GET /.
+type ClusterVersion struct {
+	Distribution string
+	Number       string
+	Major        int
+	Minor        int
+}
+
+func (v ClusterVersion) String() string {
+	return fmt.Sprintf("%s %s", v.Distribution, v.Number)
+}
+
+// Parses the response of GET /, only OpenSearch sets the distribution.
+func parseClusterVersion(info ClusterInfoResponse) (ClusterVersion, error) {
+	v := ClusterVersion{Distribution: info.Version.Distribution, Number: info.Version.Number}
+	if v.Distribution == "" {
+		v.Distribution = distributionElasticsearch
+	}
+
+	parts := strings.SplitN(v.Number, ".", 3)
+	if len(parts) < 2 {
+		return v, errors.New(500, fmt.Sprintf("Invalid cluster version: %s", v.Number))
+	}
+	var err error
+	if v.Major, err = strconv.Atoi(parts[0]); err != nil {
+		return v, errors.New(500, fmt.Sprintf("Invalid cluster version: %s", v.Number))
+	}
+	if v.Minor, err = strconv.Atoi(parts[1]); err != nil {
+		return v, errors.New(500, fmt.Sprintf("Invalid cluster version: %s", v.Number))
+	}
+	return v, nil
+}
+
+// apiAdapter holds the parts of the cluster API that differ between versions.
+type apiAdapter struct {
+	name string
+	// Query params of a snapshot listing.
+	listSnapshotsQuery string
+	// Query params of _cat/indices.
+	catIndicesQuery string
+	// Body of a restore of the given indices.
+	restoreBody func(indices []string, opts RestoreOptions) map[string]interface{}
+}
+
+// ES 2.x, 5.x and 6.x take the restored indices as a comma separated string and return 404 for missing snapshots.
+var legacyAdapter = &apiAdapter{
+	name:               "legacy",
+	listSnapshotsQuery: "",
+	catIndicesQuery:    "format=json&bytes=b",
+	restoreBody: func(indices []string, opts RestoreOptions) map[string]interface{} {
+		return restoreOptionsBody(map[string]interface{}{"indices": strings.Join(indices, ",")}, opts)
+	},
+}
+
+// ES 7.x, 8.x and OpenSearch take the restored indices as a list and skip missing snapshots when asked to.
+// Their _cat/indices has more columns, only the ones in CatIndex are requested.
+var modernAdapter = &apiAdapter{
+	name:               "modern",
+	listSnapshotsQuery: "ignore_unavailable=true",
+	catIndicesQuery:    "format=json&bytes=b&h=health,status,index,pri,rep,store.size,pri.store.size",
+	restoreBody: func(indices []string, opts RestoreOptions) map[string]interface{} {
+		return restoreOptionsBody(map[string]interface{}{"indices": indices}, opts)
+	},
+}
+
+// Adds the restore options that are the same for every version to a restore body.
+func restoreOptionsBody(body map[string]interface{}, opts RestoreOptions) map[string]interface{} {
+	if opts.Partial {
+		body["partial"] = true
+	}
+	if opts.Rename.Pattern != "" {
+		body["rename_pattern"] = opts.Rename.Pattern
+		body["rename_replacement"] = opts.Rename.Replacement
+	}
+	if len(opts.IndexSettings) > 0 {
+		body["index_settings"] = opts.IndexSettings
+	}
+	if len(opts.IgnoreIndexSettings) > 0 {
+		body["ignore_index_settings"] = opts.IgnoreIndexSettings
+	}
+	if opts.IncludeAliases != nil {
+		body["include_aliases"] = *opts.IncludeAliases
+	}
+	if opts.IncludeGlobalState != nil {
+		body["include_global_state"] = *opts.IncludeGlobalState
+	}
+	return body
+}
+
+// Returns the adapter for the cluster version, an error if the version is not supported.
+func adapterFor(v ClusterVersion) (*apiAdapter, error) {
+	switch v.Distribution {
+	case distributionElasticsearch:
+		switch v.Major {
+		case 2, 5, 6:
+			return legacyAdapter, nil
+		case 7, 8:
+			return modernAdapter, nil
+		}
+	case distributionOpenSearch:
+		switch v.Major {
+		case 1, 2:
+			return modernAdapter, nil
+		}
+	}
+	return nil, errors.New(500, fmt.Sprintf("Unsupported cluster version: %s, supported versions are Elasticsearch 2.x, 5.x, 6.x, 7.x, 8.x and OpenSearch 1.x, 2.x", v))
+}
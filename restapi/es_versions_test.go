@@ -0,0 +1,337 @@
+package restapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Clusters with responses recorded in testdata/<dir>.
+var clusterFixtures = []struct {
+	dir          string
+	distribution string
+	major        int
+	minor        int
+	adapter      string
+}{
+	{"es-2.4", distributionElasticsearch, 2, 4, "legacy"},
+	{"es-5.6", distributionElasticsearch, 5, 6, "legacy"},
+	{"es-6.8", distributionElasticsearch, 6, 8, "legacy"},
+	{"es-7.17", distributionElasticsearch, 7, 17, "modern"},
+	{"es-8.11", distributionElasticsearch, 8, 11, "modern"},
+	{"opensearch-1.3", distributionOpenSearch, 1, 3, "modern"},
+	{"opensearch-2.11", distributionOpenSearch, 2, 11, "modern"},
+}
+
+func readFixture(t *testing.T, dir string, name string) []byte {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", dir, name))
+	if err != nil {
+		t.Fatalf("Error reading fixture: %s", err)
+	}
+	return data
+}
+
+func readClusterVersion(t *testing.T, dir string) (ClusterVersion, error) {
+	var info ClusterInfoResponse
+	if err := json.Unmarshal(readFixture(t, dir, "info.json"), &info); err != nil {
+		t.Fatalf("Error decoding %s/info.json: %s", dir, err)
+	}
+	return parseClusterVersion(info)
+}
+
+func TestParseClusterVersion(t *testing.T) {
+	for _, f := range clusterFixtures {
+		v, err := readClusterVersion(t, f.dir)
+		if err != nil {
+			t.Errorf("%s: parseClusterVersion() error: %s", f.dir, err)
+			continue
+		}
+		if v.Distribution != f.distribution || v.Major != f.major || v.Minor != f.minor {
+			t.Errorf("%s: parseClusterVersion() = %s %d.%d, want %s %d.%d", f.dir, v.Distribution, v.Major, v.Minor, f.distribution, f.major, f.minor)
+		}
+
+		adapter, err := adapterFor(v)
+		if err != nil {
+			t.Errorf("%s: adapterFor() error: %s", f.dir, err)
+			continue
+		}
+		if adapter.name != f.adapter {
+			t.Errorf("%s: adapterFor() = %s, want %s", f.dir, adapter.name, f.adapter)
+		}
+	}
+
+	v, err := readClusterVersion(t, "es-1.7")
+	if err != nil {
+		t.Fatalf("es-1.7: parseClusterVersion() error: %s", err)
+	}
+	if _, err := adapterFor(v); err == nil {
+		t.Errorf("es-1.7: adapterFor() of an unsupported version returned no error")
+	}
+
+	for _, number := range []string{"", "7", "x.1", "7.x.1"} {
+		var info ClusterInfoResponse
+		info.Version.Number = number
+		if _, err := parseClusterVersion(info); err == nil {
+			t.Errorf("parseClusterVersion(%q) returned no error", number)
+		}
+	}
+}
+
+func TestRestoreBody(t *testing.T) {
+	no := false
+	yes := true
+	opts := RestoreOptions{
+		Partial:             true,
+		Rename:              Rename{Pattern: "(.+)", Replacement: "restored-$1"},
+		IndexSettings:       map[string]string{"index.number_of_replicas": "0"},
+		IgnoreIndexSettings: []string{"index.routing.allocation.require.box_type"},
+		IncludeAliases:      &no,
+		IncludeGlobalState:  &yes,
+		Alias:               "restored-logs",
+	}
+	indices := []string{"test-v1-2016_098", "test-v1-2016_099"}
+
+	tests := []struct {
+		adapter *apiAdapter
+		opts    RestoreOptions
+		want    string
+	}{
+		{legacyAdapter, RestoreOptions{}, `{"indices": "test-v1-2016_098,test-v1-2016_099"}`},
+		{modernAdapter, RestoreOptions{}, `{"indices": ["test-v1-2016_098", "test-v1-2016_099"]}`},
+		{legacyAdapter, opts, `{
+			"indices": "test-v1-2016_098,test-v1-2016_099",
+			"partial": true,
+			"rename_pattern": "(.+)",
+			"rename_replacement": "restored-$1",
+			"index_settings": {"index.number_of_replicas": "0"},
+			"ignore_index_settings": ["index.routing.allocation.require.box_type"],
+			"include_aliases": false,
+			"include_global_state": true
+		}`},
+		{modernAdapter, opts, `{
+			"indices": ["test-v1-2016_098", "test-v1-2016_099"],
+			"partial": true,
+			"rename_pattern": "(.+)",
+			"rename_replacement": "restored-$1",
+			"index_settings": {"index.number_of_replicas": "0"},
+			"ignore_index_settings": ["index.routing.allocation.require.box_type"],
+			"include_aliases": false,
+			"include_global_state": true
+		}`},
+	}
+
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.adapter.restoreBody(indices, tt.opts))
+		if err != nil {
+			t.Fatalf("%s: error encoding restore body: %s", tt.adapter.name, err)
+		}
+		if !sameJSON(t, data, []byte(tt.want)) {
+			t.Errorf("%s: restoreBody() = %s, want %s", tt.adapter.name, data, tt.want)
+		}
+	}
+}
+
+// Requests and restore bodies each adapter has to send.
+var adapterRequests = map[string]struct {
+	listSnapshots string
+	catIndices    string
+	restoreBody   string
+}{
+	"legacy": {
+		listSnapshots: "/_snapshot/test/_all",
+		catIndices:    "/_cat/indices?format=json&bytes=b",
+		restoreBody:   `{"indices": "test-v1-2016_098,test-v1-2016_099", "rename_pattern": "(.+)", "rename_replacement": "restored-$1"}`,
+	},
+	"modern": {
+		listSnapshots: "/_snapshot/test/_all?ignore_unavailable=true",
+		catIndices:    "/_cat/indices?format=json&bytes=b&h=health,status,index,pri,rep,store.size,pri.store.size",
+		restoreBody:   `{"indices": ["test-v1-2016_098", "test-v1-2016_099"], "rename_pattern": "(.+)", "rename_replacement": "restored-$1"}`,
+	},
+}
+
+// Runs every call that differs between versions against the recorded responses of each cluster version.
+func TestHTTPBackendFixtures(t *testing.T) {
+	defer setConfig(currentConfig())
+
+	indices := []string{"test-v1-2016_098", "test-v1-2016_099"}
+	opts := RestoreOptions{Rename: Rename{Pattern: "(.+)", Replacement: "restored-$1"}}
+
+	for _, f := range clusterFixtures {
+		want := adapterRequests[f.adapter]
+		responses := map[string][]byte{
+			"GET /":                     readFixture(t, f.dir, "info.json"),
+			"GET /_cluster/health":      readFixture(t, f.dir, "health.json"),
+			"GET " + want.listSnapshots: readFixture(t, f.dir, "snapshots.json"),
+			"GET " + want.catIndices:    readFixture(t, f.dir, "cat_indices.json"),
+			"GET /test-v1-2016_098,test-v1-2016_099/_recovery?ignore_unavailable=true": readFixture(t, f.dir, "recovery.json"),
+			"POST /_snapshot/test/snap/_restore":                                       []byte(`{"accepted": true}`),
+		}
+
+		var restoreBody []byte
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			request := r.Method + " " + r.URL.RequestURI()
+			response, ok := responses[request]
+			if !ok {
+				http.Error(w, fmt.Sprintf(`{"error": "unexpected request: %s"}`, request), http.StatusBadRequest)
+				return
+			}
+			if r.Method == "POST" {
+				restoreBody, _ = ioutil.ReadAll(r.Body)
+			}
+			w.Write(response)
+		}))
+
+		setConfig(&Config{Elasticsearch: ElasticsearchConfig{Host: srv.URL}, client: srv.Client(), timeout: 5 * time.Second})
+		b := NewHTTPBackend()
+		ctx := context.Background()
+
+		health, err := b.ClusterHealth(ctx)
+		if err != nil || health != "yellow" {
+			t.Errorf("%s: ClusterHealth() = %q, %v, want yellow", f.dir, health, err)
+		}
+
+		snapshots, err := b.ListSnapshots(ctx, "test")
+		if err != nil {
+			t.Errorf("%s: ListSnapshots() error: %s", f.dir, err)
+		}
+		got := make([]string, 0)
+		for _, s := range snapshots {
+			got = append(got, fmt.Sprintf("%s %s %d %v", s.Snapshot, s.State, s.StartTimeInMillis, s.Indices))
+		}
+		wantSnapshots := []string{
+			"snap SUCCESS 1700000000000 [test-v1-2016_098 test-v1-2016_099]",
+			"snap-2 PARTIAL 1700086400000 [test-v1-2016_099]",
+		}
+		if !reflect.DeepEqual(got, wantSnapshots) {
+			t.Errorf("%s: ListSnapshots() = %v, want %v", f.dir, got, wantSnapshots)
+		}
+
+		cat, err := b.CatIndices(ctx)
+		if err != nil {
+			t.Errorf("%s: CatIndices() error: %s", f.dir, err)
+		}
+		wantCat := []CatIndex{
+			{Health: "green", Status: "open", Index: "test-v1-2016_098", Primaries: "2", Replicas: "1", StoreSize: "209715200", PriStoreSize: "104857600"},
+			{Health: "red", Status: "open", Index: "restored-test-v1-2016_099", Primaries: "1", Replicas: "0"},
+		}
+		if !reflect.DeepEqual(cat, wantCat) {
+			t.Errorf("%s: CatIndices() = %+v, want %+v", f.dir, cat, wantCat)
+		}
+
+		if err := b.Restore(ctx, "test/snap", indices, opts); err != nil {
+			t.Errorf("%s: Restore() error: %s", f.dir, err)
+		}
+		if b.adapter == nil || b.adapter.name != f.adapter {
+			t.Errorf("%s: backend did not detect the %s API", f.dir, f.adapter)
+		}
+		if !sameJSON(t, restoreBody, []byte(want.restoreBody)) {
+			t.Errorf("%s: restore body = %s, want %s", f.dir, restoreBody, want.restoreBody)
+		}
+
+		recoveries, err := b.RecoveryStatus(ctx, indices)
+		if err != nil {
+			t.Errorf("%s: RecoveryStatus() error: %s", f.dir, err)
+		}
+		gotRecoveries := make(map[string][]string)
+		for name, shards := range recoveries {
+			for _, shard := range shards {
+				gotRecoveries[name] = append(gotRecoveries[name], shardSummary(shard))
+			}
+		}
+		wantRecoveries := map[string][]string{
+			"test-v1-2016_098": {"0 SNAPSHOT DONE primary 52428800/52428800", "1 SNAPSHOT INDEX primary 20971520/52428800"},
+			"test-v1-2016_099": {"0 SNAPSHOT INIT primary 0/41943040"},
+		}
+		if !reflect.DeepEqual(gotRecoveries, wantRecoveries) {
+			t.Errorf("%s: RecoveryStatus() = %v, want %v", f.dir, gotRecoveries, wantRecoveries)
+		}
+
+		srv.Close()
+	}
+}
+
+func shardSummary(s ShardRecovery) string {
+	primary := "replica"
+	if s.Primary {
+		primary = "primary"
+	}
+	return fmt.Sprintf("%d %s %s %s %d/%d", s.ID, s.Type, s.Stage, primary, s.Index.Size.RecoveredInBytes, s.Index.Size.TotalInBytes)
+}
+
+// Returns true if the two JSON documents are equal, regardless of formatting and key order.
+func sameJSON(t *testing.T, a []byte, b []byte) bool {
+	var va, vb interface{}
+	if err := json.Unmarshal(a, &va); err != nil {
+		t.Errorf("Error decoding JSON %s: %s", a, err)
+		return false
+	}
+	if err := json.Unmarshal(b, &vb); err != nil {
+		t.Errorf("Error decoding JSON %s: %s", b, err)
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// A detection that hangs on the cluster does not hold up other requests that detect the version.
+func TestHTTPBackendConcurrentDetection(t *testing.T) {
+	defer setConfig(currentConfig())
+
+	info := readFixture(t, "es-7.17", "info.json")
+	hang := make(chan struct{})
+	var first sync.Once
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hung := false
+		first.Do(func() { hung = true })
+		if hung {
+			<-hang
+		}
+		w.Write(info)
+	}))
+	defer srv.Close()
+	defer close(hang)
+
+	setConfig(&Config{Elasticsearch: ElasticsearchConfig{Host: srv.URL}, client: srv.Client(), timeout: 5 * time.Second})
+	b := NewHTTPBackend()
+
+	started := make(chan struct{})
+	go func() {
+		close(started)
+		b.Version(context.Background())
+	}()
+	<-started
+	time.Sleep(100 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	var version ClusterVersion
+	var err error
+	done := make(chan struct{})
+	go func() {
+		version, err = b.Version(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Version() is held up by another detection")
+	}
+	if err != nil {
+		t.Fatalf("Version() while another detection hangs: %s", err)
+	}
+	if version.Major != 7 || version.Minor != 17 {
+		t.Errorf("Version() = %s, want 7.17", version)
+	}
+
+	// The detected version is kept for later requests.
+	if adapter, _, err := b.api(ctx); err != nil || adapter != modernAdapter {
+		t.Errorf("api() after detection = %v, %v, want the modern adapter", adapter, err)
+	}
+}
@@ -1,18 +1,19 @@
 package restapi
 
 import (
-	"encoding/json"
+	"context"
 	"log"
 	"fmt"
-	"net/http"
+	"os"
 	"path"
-	"sort"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	errors "github.com/go-openapi/errors"
 	strftime "github.com/hhkbp2/go-strftime"
-	elastic "gopkg.in/olivere/elastic.v2"
 
 	"github.com/danisla/esio/models"
 )
@@ -30,6 +31,7 @@ type Snapshot struct {
 	VersionId int 	 		`json:"version_id"`
 	Indices   []string 	`json:"indices"`
 	State     string    `json:"state"`
+	StartTimeInMillis int64 `json:"start_time_in_millis"`
 }
 
 type CatIndex struct {
@@ -42,223 +44,431 @@ type CatIndex struct {
 	PriStoreSize string `json:"pri.store.size"`
 }
 
-type SnapshotRestoreResponse struct {
-	Snapshot SnapshotRestore `json:"snapshot"`
-}
-
-type SnapshotRestore struct {
-	Snapshot string         `json:"snapshot"`
-	Indices  []string       `json:"indices"`
-	Shards   SnapshotShards `json:"shards"`
-}
-
-type SnapshotShards struct {
-	Total      int `json:"total"`
-	Failed     int `json:"failed"`
-	Successful int `json:"successful"`
+type RestoreAcceptedResponse struct {
+	Accepted bool `json:"accepted"`
 }
 
 var restoreQueue *Queue
 var deleteQueue *Queue
+var restorePool *RestorePool
 
-func initQueues() {
+// Creates the queues and loads the persisted state, workers are only started if the state could be loaded.
+func initQueues() error {
 	restoreQueue = NewQueue(1)
 	deleteQueue = NewQueue(1)
+	cfg := currentConfig()
+	restorePool = NewRestorePool(cfg.Queues.MaxRestore)
+	diskGuard = NewDiskGuard(cfg.Limits.DiskWatermark)
+
+	jobsPath := ""
+	leasesPath := ""
+	accessPath := ""
+	optionsPath := ""
+	if cfg.Queues.StateDir != "" {
+		if err := os.MkdirAll(cfg.Queues.StateDir, 0755); err != nil {
+			return &startupError{exitStateUnavailable, "State unavailable", []string{fmt.Sprintf("could not create state dir '%s': %s", cfg.Queues.StateDir, err)}}
+		}
+		jobsPath = filepath.Join(cfg.Queues.StateDir, "jobs.json")
+		leasesPath = filepath.Join(cfg.Queues.StateDir, "leases.json")
+		accessPath = filepath.Join(cfg.Queues.StateDir, "access.json")
+		optionsPath = filepath.Join(cfg.Queues.StateDir, "restore-options.json")
+	}
+
+	problems := make([]string, 0)
+	var err error
+	if jobStore, err = NewJobStore(jobsPath); err != nil {
+		problems = append(problems, fmt.Sprintf("could not load jobs: %s", err))
+	}
+	if leaseStore, err = NewLeaseStore(leasesPath); err != nil {
+		problems = append(problems, fmt.Sprintf("could not load leases: %s", err))
+	}
+	if accessLog, err = NewAccessLog(accessPath); err != nil {
+		problems = append(problems, fmt.Sprintf("could not load access log: %s", err))
+	}
+	if restoreOptionsStore, err = NewRestoreOptionsStore(optionsPath); err != nil {
+		problems = append(problems, fmt.Sprintf("could not load restore options: %s", err))
+	}
+	if len(problems) > 0 {
+		return &startupError{exitStateUnavailable, "State unavailable", problems}
+	}
+
+	if cfg.Queues.StateDir != "" {
+		if err := replayQueues(cfg.Queues.StateDir); err != nil {
+			return &startupError{exitStateUnavailable, "State unavailable", []string{fmt.Sprintf("could not replay queues from state dir: %s", err)}}
+		}
+	}
 
 	// Start queue workers
 
-	// Restore queue worker
+	// Restore queue worker, runs up to restorePool.Max() restores at once.
+	// Each restore takes every queued index of the snapshot of the first index that is not deferred.
 	go func() {
 		for {
-			// Restores 1 index at a time based on what is in the restoreQueue
-			// TODO dequeue all available, then group by repo/snapshot and perform bulk restore.
-
-			for restoreQueue.count > 0 {
-				node := restoreQueue.Pop()
-				index := node.Value
-
-				// log.Println(fmt.Sprintf("Restoring index: %s, remaining in queue: %d", index, restoreQueue.count))
-				time.Sleep(1000 * time.Millisecond)
-
-				res, err := restoreSnapshot(index)
-				if err != nil {
-					log.Println(fmt.Sprintf("ERROR: could not restore index: %s, error: %s", index, err))
-				} else if !stringInList(res.Indices, path.Base(index)) {
-					log.Println(fmt.Sprintf("ERROR: index was not in list of restored indices: %s", res.Indices))
-				} else if res.Shards.Successful != res.Shards.Total {
-					log.Println(fmt.Sprintf("ERROR: not all shards for index '%s' were successfully recovered.", index))
-				} else {
-					log.Println(fmt.Sprintf("Successfully recovered index: %s", index))
+			for restoreQueue.Len() > 0 && restorePool.acquire() {
+				nodes := restoreQueue.PopGroup(diskGuard.Ready, sameRestore)
+				if nodes == nil {
+					restorePool.release()
+					break
 				}
 
+				go func(nodes []*Node) {
+					defer restorePool.release()
+					restoreIndices(nodes)
+				}(nodes)
 			}
 			time.Sleep(2000 * time.Millisecond)
 		}
-  }()
+	}()
 
 	// Delete queue worker
 	go func() {
 		for {
-			for deleteQueue.count > 0 {
+			for deleteQueue.Len() > 0 {
 				node := deleteQueue.Pop()
-				index := node.Value
 
 				time.Sleep(1000 * time.Millisecond)
 
-				client, err := elastic.NewClient(
-					elastic.SetURL(myFlags.EsHost),
-					elastic.SetHealthcheck(false),
-					elastic.SetSniff(false))
-				if err != nil {
-					log.Println(fmt.Sprintf("ERROR creating Elastic client for index delete: %s", err))
-				} else {
-					_, err = client.DeleteIndex(path.Base(index)).Do()
-					if err != nil {
-						log.Println(fmt.Sprintf("ERROR deleting ES index '%s': %s", path.Base(index), err))
-					} else {
-						log.Println(fmt.Sprintf("Successfully deleted index: %s", index))
-					}
-				}
+				deleteIndex(node)
 			}
 			time.Sleep(2000 * time.Millisecond)
 		}
 	}()
+
+	// Lease reaper, queues restored indices whose TTL has expired for delete.
+	go func() {
+		for {
+			time.Sleep(reapInterval)
+			reapExpiredIndices()
+		}
+	}()
+
+	// Alias watcher, attaches the alias of restored indices once they are green.
+	go func() {
+		for {
+			time.Sleep(aliasPollInterval)
+			attachAliases()
+		}
+	}()
+
+	return nil
 }
 
-// Create a list of indices to be restored from the given start,end range.
-// Snapshots are derived from the given repoPattern and discritized at intervals of given indexResolution
-func makeIndexListFromRange(start time.Time, end time.Time, indexResolution string, repoPattern string) ([]string, error) {
-	a := make([]string, 0)
+// Deletes a single index popped from the deleteQueue and logs the outcome.
+func deleteIndex(node *Node) {
+	index := node.Value
+	defer deleteQueue.Done(node)
 
-	// starting from start time, make index pattern
-	// Increment start time by IndexResolution
-	// Add next interval to list until time exceedes end time.
+	jobStore.Started(jobTypeDelete, []string{index})
 
-	var t = start
+	// Consumers of the alias stop seeing the index before it is deleted.
+	aliasManager.Detach(index)
+	defer aliasManager.Done(index)
 
-	for t.Before(end) {
-		a = append(a, strftime.Format(repoPattern, t))
-		switch indexResolution {
-			case "day": t = t.AddDate(0,0,1)
-			case "month": t = t.AddDate(0,1,0)
-			case "year": t = t.AddDate(1,0,0)
-			default:
-				return a, errors.New(400, "Invalid index resolution: " + indexResolution)
-		}
+	name := restoredName(index)
+	err := backend.DeleteIndex(context.Background(), name)
+	if err != nil {
+		log.Println(fmt.Sprintf("ERROR deleting ES index '%s': %s", name, err))
+	} else {
+		log.Println(fmt.Sprintf("Successfully deleted index: %s", index))
+		accessLog.Forget([]string{index})
+		restoreOptionsStore.Forget([]string{index})
 	}
-	return a, nil
+
+	jobStore.Finished(jobTypeDelete, index, err)
 }
 
-// Verifies each index pattern in given list is found on the ES cluster.
-func validateSnapshotIndex(repoPattern string) (bool, error) {
-	repo := path.Dir(repoPattern)
-	target := path.Base(repoPattern)
-	endpoint := fmt.Sprintf("%s/_snapshot/%s", myFlags.EsHost, repo)
+// Returns true if the two repo/snap/index paths are in the same snapshot and have the same restore options,
+// so they can be restored together.
+func sameRestore(a string, b string) bool {
+	return path.Dir(a) == path.Dir(b) && sameRestoreOptions(restoreOptionsStore.Get(a), restoreOptionsStore.Get(b))
+}
+
+// Restores a group of indices popped from the restoreQueue with a single bulk restore of their snapshot
+// and logs the outcome for each index.
+func restoreIndices(nodes []*Node) {
+	snapshot := path.Dir(nodes[0].Value)
+	indices := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		indices = append(indices, path.Base(node.Value))
+		defer restoreQueue.Done(node)
+	}
 
-	log.Println("Checking snapshot: " + endpoint + " for index: " + target)
+	values := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		values = append(values, node.Value)
+	}
 
-	req, err := http.NewRequest("GET", endpoint, nil)
+	// Make room under the restored data budget and check the restore fits on the cluster disk.
+	// Deferred indices go back on the queue.
+	var reserved int64
+	admitted, err := makeRoom(snapshot, values)
+	if admitted {
+		admitted, reserved, err = diskGuard.Admit(snapshot, values)
+		if !admitted {
+			restoreBudget.Release(values)
+		}
+	}
 	if err != nil {
-		return false, errors.New(500, fmt.Sprintf("Error building http request: %s", err))
+		diskGuard.Forget(values)
+		log.Println(fmt.Sprintf("ERROR: refusing to restore indices from snapshot: %s, error: %s", snapshot, err))
+		for _, index := range values {
+			jobStore.Finished(jobTypeRestore, index, err)
+		}
+		return
 	}
+	if !admitted {
+		reason, _ := diskGuard.Deferred(values[0])
+		log.Println(fmt.Sprintf("Deferred restore of %d indices from snapshot: %s, reason: %s", len(indices), snapshot, reason))
+		for _, node := range nodes {
+			restoreQueue.Push(node)
+		}
+		return
+	}
+	defer diskGuard.Release(reserved)
+	defer restoreBudget.Release(values)
+
+	log.Println(fmt.Sprintf("Restoring %d indices from snapshot: %s, active restores: %d/%d", len(indices), snapshot, restorePool.Active(), restorePool.Max()))
 
-	client := &http.Client{}
+	jobStore.Started(jobTypeRestore, values)
+
+	// Indices of a PARTIAL snapshot can only be restored with their missing shards left out. Restores
+	// replayed on startup can be of repos that are not in the catalog yet.
+	state, ok := snapshotCatalog.State(path.Dir(snapshot), path.Base(snapshot))
+	if !ok {
+		snapshotCatalog.Refresh(context.Background(), path.Dir(snapshot), catalogMissRefresh)
+		state, _ = snapshotCatalog.State(path.Dir(snapshot), path.Base(snapshot))
+	}
+	opts := restoreOptionsStore.Get(values[0])
+	opts.Partial = state == snapshotPartial
 
-	resp, err := client.Do(req)
+	err = backend.Restore(context.Background(), snapshot, indices, opts)
 	if err != nil {
-		return false, errors.New(500, fmt.Sprintf("Error making client request: %s", err))
+		log.Println(fmt.Sprintf("ERROR: could not restore indices from snapshot: %s, error: %s", snapshot, err))
+		restoreOptionsStore.Forget(values)
+		for _, index := range values {
+			jobStore.Finished(jobTypeRestore, index, err)
+		}
+		return
 	}
 
-	defer resp.Body.Close()
+	// The indices are on the cluster once the restore is accepted and count against the restored data
+	// budget, even if their recovery fails.
+	for _, index := range values {
+		accessLog.Restored(index)
+	}
 
-	var snap SnapshotResponse
+	results := waitForRecovery(values)
 
-	if err := json.NewDecoder(resp.Body).Decode(&snap); err != nil {
-		// TODO: need to test this
-		return false, errors.New(500, fmt.Sprintf("Error decoding ES JSON response for url: %s", endpoint))
+	for _, index := range values {
+		err := results[index]
+		if err != nil {
+			log.Println(fmt.Sprintf("ERROR: %s", err))
+		} else {
+			log.Println(fmt.Sprintf("Successfully recovered index: %s", index))
+		}
+		jobStore.Finished(jobTypeRestore, index, err)
 	}
+}
 
-	if len(snap.Snapshots) == 0 {
-		return false, errors.New(404, fmt.Sprintf("No snapshots found in repo: %s", repo))
+// Loads the restore and delete queues from their journals in the given directory.
+// Items that were being worked on when the server stopped are re-checked against _cat/indices:
+// interrupted restores are retried if the index is not on the cluster and interrupted deletes
+// are retried if the index is still on the cluster.
+func replayQueues(stateDir string) error {
+	// If the cluster can't be reached, assume every interrupted item still needs work.
+	recheck := true
+	online := make([]string, 0)
+	onlineIndices, err := backend.CatIndices(context.Background())
+	if err != nil {
+		log.Println(fmt.Sprintf("WARN: could not re-check interrupted queue items against _cat/indices: %s", err))
+		recheck = false
+	}
+	for _, i := range onlineIndices {
+		online = append(online, i.Index)
 	}
 
-	for _, snapshot := range snap.Snapshots {
-		var indices = snapshot.Indices
-		sort.Strings(indices)
-
-		if stringInList(indices, target) {
-			if snapshot.State != "SUCCESS" {
-				return false, errors.New(400, fmt.Sprintf("Snapshot state was not 'SUCCESS': %s", snap))
-			}
-			return true, nil
+	err = replayQueue(restoreQueue, filepath.Join(stateDir, "restore-queue.json"), func(indice string) bool {
+		if !recheck || !stringInList(online, restoredName(indice)) {
+			return true
 		}
+		jobStore.Finished(jobTypeRestore, indice, nil)
+		return false
+	})
+	if err != nil {
+		return err
 	}
 
-	return false, errors.New(404, fmt.Sprintf("Index with name '%s' not found in repo: '%s'", target, snap))
+	return replayQueue(deleteQueue, filepath.Join(stateDir, "delete-queue.json"), func(indice string) bool {
+		if !recheck || stringInList(online, restoredName(indice)) {
+			return true
+		}
+		jobStore.Finished(jobTypeDelete, indice, nil)
+		return false
+	})
 }
 
-func restoreSnapshot(snap string) (*SnapshotRestore, error) {
-	repo := path.Dir(snap)
-	indices := path.Base(snap)
+// Create a list of indices to be restored from the given start,end range.
+// Snapshots are derived from the given repoPattern and discritized at intervals of given indexResolution.
+// Every index whose interval overlaps [start,end] is returned, including the one that starts at end.
+func makeIndexListFromRange(start time.Time, end time.Time, indexResolution string, repoPattern string) ([]string, error) {
+	a := make([]string, 0)
 
-	log.Println(fmt.Sprintf("Restoring Snapshot from repo: %s with indices: %s", repo, indices))
+	// Starting from start time floored to the IndexResolution boundary, make index pattern
+	// Increment time by IndexResolution
+	// Add next interval to list until time exceedes end time.
 
-	endpoint := fmt.Sprintf("%s/_snapshot/%s/_restore?wait_for_completion=true", myFlags.EsHost, repo)
+	if err := validateRepoPattern(repoPattern); err != nil {
+		return a, err
+	}
 
-	data := fmt.Sprintf(`{"indices":"%s"}`,indices)
-  buf := strings.NewReader(data)
-	resp, err := http.Post(endpoint, "application/json", buf)
+	res, err := parseResolution(indexResolution)
 	if err != nil {
-		return nil, errors.New(500, fmt.Sprintf("HTTP Request error on POST %s", endpoint))
+		return a, err
 	}
 
-	defer resp.Body.Close()
+	var t = res.floor(start)
 
-	var snapRestore SnapshotRestoreResponse
-
-	if err := json.NewDecoder(resp.Body).Decode(&snapRestore); err != nil {
-		// TODO: need to test this
-		return &snapRestore.Snapshot, errors.New(500, fmt.Sprintf("Error decoding ES JSON response for url: %s", endpoint))
+	for !t.After(end) {
+		// Durations shorter than the repo pattern yield the same index more than once.
+		indice := strftime.Format(repoPattern, t)
+		if len(a) == 0 || a[len(a)-1] != indice {
+			a = append(a, indice)
+		}
+		t = res.step(t)
 	}
+	return a, nil
+}
 
-	return &snapRestore.Snapshot, nil
+// resolution floors a time to the start of its index interval and steps to the start of the next one.
+type resolution struct {
+	floor func(time.Time) time.Time
+	step  func(time.Time) time.Time
 }
 
-func getIndices() ([]CatIndex, error) {
-	// var cat CatIndices
-	cat := make([]CatIndex,0)
+// Parses an index resolution of 'hour', 'day', 'week' (ISO week, ex: %Y-%W), 'month', 'year' or a duration like 6h.
+// Durations are floored to a multiple of the duration since the zero time on the wall clock of the time's zone.
+func parseResolution(indexResolution string) (resolution, error) {
+	switch indexResolution {
+		case "hour": return resolution{
+			floor: func(t time.Time) time.Time { return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location()) },
+			step: func(t time.Time) time.Time { return t.Add(time.Hour) },
+		}, nil
+		case "day": return resolution{
+			floor: func(t time.Time) time.Time { return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()) },
+			step: func(t time.Time) time.Time { return t.AddDate(0,0,1) },
+		}, nil
+		case "week": return resolution{
+			floor: func(t time.Time) time.Time {
+				// ISO weeks start on Monday.
+				offset := (int(t.Weekday()) + 6) % 7
+				return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+			},
+			step: func(t time.Time) time.Time { return t.AddDate(0,0,7) },
+		}, nil
+		case "month": return resolution{
+			floor: func(t time.Time) time.Time { return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()) },
+			step: func(t time.Time) time.Time { return t.AddDate(0,1,0) },
+		}, nil
+		case "year": return resolution{
+			floor: func(t time.Time) time.Time { return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location()) },
+			step: func(t time.Time) time.Time { return t.AddDate(1,0,0) },
+		}, nil
+	}
+
+	d, err := time.ParseDuration(indexResolution)
+	if err != nil || d < time.Minute {
+		return resolution{}, errors.New(400, "Invalid index resolution: " + indexResolution)
+	}
+	// Truncate works on absolute time, intervals are computed on the wall clock of the time's zone so they
+	// start on local boundaries also when the zone offset changes, ex: for DST.
+	wall := func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	}
+	local := func(w time.Time, loc *time.Location) time.Time {
+		return time.Date(w.Year(), w.Month(), w.Day(), w.Hour(), w.Minute(), w.Second(), w.Nanosecond(), loc)
+	}
+	return resolution{
+		floor: func(t time.Time) time.Time { return local(wall(t).Truncate(d), t.Location()) },
+		step: func(t time.Time) time.Time {
+			next := local(wall(t).Add(d), t.Location())
+			// Wall clock times repeated when the clocks go back can map to an earlier time.
+			if !next.After(t) {
+				next = t.Add(d)
+			}
+			return next
+		},
+	}, nil
+}
 
+// Resolves a repo/snap/index path, where snap is a snapshot name or glob, to the path of the snapshot the
+// index is restored from. A snapshot the index was already restored from is kept, otherwise the newest
+// SUCCESS snapshot with the index is chosen, or the newest PARTIAL one if there is none and partial
+// snapshots are accepted.
+func resolveSnapshotIndex(ctx context.Context, repoPattern string) (string, error) {
+	selector := path.Dir(repoPattern)
+	repo := path.Dir(selector)
+	target := path.Base(repoPattern)
 
-	endpoint := fmt.Sprintf("%s/_cat/indices?format=json", myFlags.EsHost)
+	log.Println("Checking snapshot: " + selector + " for index: " + target)
 
-	req, err := http.NewRequest("GET", endpoint, nil)
+	snapshots, err := snapshotCatalog.Snapshots(ctx, repo, path.Base(selector), target)
 	if err != nil {
-		return cat, errors.New(500, fmt.Sprintf("Error building http request: %s", err))
+		return "", err
 	}
 
-	client := &http.Client{}
+	if len(snapshots) == 0 {
+		return "", errors.New(404, fmt.Sprintf("Index with name '%s' not found in snapshot: '%s'", target, selector))
+	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return cat, errors.New(500, fmt.Sprintf("Error making client request: %s", err))
+	// An index restored, or queued for restore, from one of the snapshots keeps resolving to it when newer
+	// snapshots are taken, so its status, lease, alias and delete refer to the copy on the cluster.
+	for _, snapshot := range snapshots {
+		indice := path.Join(repo, snapshot.Snapshot, target)
+		if _, tracked := restoreOptionsStore.Lookup(indice); tracked {
+			return indice, nil
+		}
 	}
 
-	defer resp.Body.Close()
+	var partial *Snapshot
+	states := make([]string, 0, len(snapshots))
+	for i, snapshot := range snapshots {
+		if snapshot.State == snapshotSuccess {
+			return path.Join(repo, snapshot.Snapshot, target), nil
+		}
+		if snapshot.State == snapshotPartial && partial == nil {
+			partial = &snapshots[i]
+		}
+		states = append(states, fmt.Sprintf("%s (%s)", snapshot.Snapshot, snapshot.State))
+	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&cat); err != nil {
-		// TODO: need to test this
-		return cat, errors.New(500, fmt.Sprintf("Error decoding ES JSON response for url: %s", endpoint))
+	if partial != nil && currentConfig().Indices.AcceptPartial {
+		return path.Join(repo, partial.Snapshot, target), nil
 	}
 
-	return cat, nil
+	return "", errors.New(400, fmt.Sprintf("No snapshot of index '%s' was 'SUCCESS', found: %s", target, strings.Join(states, ", ")))
+}
+
+// Resolves each repo/snap/index path of the list, see resolveSnapshotIndex.
+func resolveSnapshotIndices(ctx context.Context, indices []string) ([]string, error) {
+	resolved := make([]string, 0, len(indices))
+	for _, i := range indices {
+		r, err := resolveSnapshotIndex(ctx, i)
+		if err != nil {
+			return resolved, fmt.Errorf("%s: %s", i, err)
+		}
+		resolved = append(resolved, r)
+	}
+	return resolved, nil
 }
 
 // Takes a list of indices and matches it against the found indices
-// Populates the []Ready, []Pending and []Restoring arrays of the IndiceStatus struct.
-func makeIndexStatus(indices []string) (models.IndiceStatus, error) {
-	var status = &models.IndiceStatus{Pending: make([]string, 0), Ready: make([]string, 0), Restoring: make([]string, 0), Deleting: make([]string, 0)}
+// Populates the []Ready, []Pending, []Restoring and []Deferred arrays of the IndiceStatus struct, along with
+// the snapshot of each index. Indices of an active restore job that are not on the cluster yet are Restoring. Online indices are matched by the name each index was restored under, indices
+// esio has no restore options for are matched by the name given by rename if it is not nil.
+func makeIndexStatus(ctx context.Context, indices []string, rename *Rename) (models.IndiceStatus, error) {
+	var status = &models.IndiceStatus{Pending: make([]string, 0), Ready: make([]string, 0), Restoring: make([]string, 0), Deleting: make([]string, 0), Deferred: make([]string, 0), DeferredReasons: make(map[string]string), Progress: make(map[string]models.RestoreProgress), Snapshots: make(map[string]string), Partial: make([]string, 0)}
 
-	onlineIndices, err := getIndices()
+	onlineIndices, err := backend.CatIndices(ctx)
 	if err != nil {
 		return *status, errors.New(500, fmt.Sprintf("Could not GET _cat/indices from Elasticsearch: %s", err))
 	}
@@ -273,7 +483,11 @@ func makeIndexStatus(indices []string) (models.IndiceStatus, error) {
 
 		// Match online index to availalbe indice in snapshot repo.
 		for _, i := range indices {
-			if path.Base(i) == onlineIndice.Index {
+			name := restoredName(i)
+			if _, tracked := restoreOptionsStore.Lookup(i); !tracked && rename != nil {
+				name = rename.apply(path.Base(i))
+			}
+			if name == onlineIndice.Index {
 				match = i
 				found = true
 				break
@@ -298,6 +512,10 @@ func makeIndexStatus(indices []string) (models.IndiceStatus, error) {
 	// Find all indices that are pending (not found in onlineIndices)
 	allOnlineIndices := concat(status.Ready, status.Restoring)
 
+	// Indices a restore worker has taken off the queue are restoring until their job is done with them,
+	// even before they show up on the cluster.
+	restoring := jobStore.PendingIndices(jobTypeRestore)
+
 	for _, indice := range indices {
 		// Verify index is not in the Ready or Restoring lists
 		found = false
@@ -306,11 +524,20 @@ func makeIndexStatus(indices []string) (models.IndiceStatus, error) {
 		deleting := deleteQueue.Contains(indice)
 
 		if !found && !queued && !deleting {
-			status.Pending = append(status.Pending, indice)
+			if stringInList(restoring, indice) {
+				status.Restoring = append(status.Restoring, indice)
+			} else {
+				status.Pending = append(status.Pending, indice)
+			}
 		}
 
 		if queued {
-			status.Restoring = append(status.Restoring, indice)
+			if reason, deferred := diskGuard.Deferred(indice); deferred {
+				status.Deferred = append(status.Deferred, indice)
+				status.DeferredReasons[indice] = reason
+			} else {
+				status.Restoring = append(status.Restoring, indice)
+			}
 		}
 
 		if deleting {
@@ -318,27 +545,77 @@ func makeIndexStatus(indices []string) (models.IndiceStatus, error) {
 		}
 	}
 
+	for _, indice := range status.Restoring {
+		if progress, ok := recoveryTracker.Progress(indice); ok {
+			status.Progress[indice] = progress
+		}
+	}
+
+	for _, indice := range indices {
+		snapshot := path.Dir(indice)
+		status.Snapshots[indice] = path.Base(snapshot)
+		if state, _ := snapshotCatalog.State(path.Dir(snapshot), path.Base(snapshot)); state == snapshotPartial {
+			status.Partial = append(status.Partial, indice)
+		}
+	}
+
 	return *status, nil
 }
 
-func deleteIndices(indices []string) (bool, error) {
+// Returns the indices in the given list that esio restored, are ready and not already queued for delete. If
+// rename is not nil only indices restored with that rename are returned.
+func deletableIndices(ctx context.Context, indices []string, rename *Rename) ([]string, error) {
+	deletable := make([]string, 0)
+
 	// Create the IndexStatus data structure
-	indiceStatus, err := makeIndexStatus(indices)
+	indiceStatus, err := makeIndexStatus(ctx, indices, nil)
 	if err != nil {
-		return false, errors.New(500, fmt.Sprintf("Error comparing online indices with snapshots list: %s", err))
+		return deletable, errors.New(500, fmt.Sprintf("Error comparing online indices with snapshots list: %s", err))
 	}
 
-	var deleting = false
-
 	for _, indice := range indices {
 		queued := stringInList(indiceStatus.Deleting, indice)
-		if stringInList(indiceStatus.Ready, indice) && !queued {
-			deleteQueue.Push(&Node{indice})
-			deleting = true
+		if !stringInList(indiceStatus.Ready, indice) || queued {
+			continue
+		}
+		// Indices esio has no restore options for were not restored by it, they are matched by their base
+		// name and may be live indices.
+		opts, tracked := restoreOptionsStore.Lookup(indice)
+		if !tracked || (rename != nil && opts.Rename != *rename) {
+			continue
+		}
+		deletable = append(deletable, indice)
+	}
+
+	return deletable, nil
+}
+
+// Returns the indices in the given list that esio restored, or is restoring, with restore options other
+// than opts. They can't be restored again with opts until they are deleted.
+func conflictingIndices(ctx context.Context, indices []string, opts RestoreOptions) ([]string, error) {
+	conflicts := make([]string, 0)
+
+	indiceStatus, err := makeIndexStatus(ctx, indices, nil)
+	if err != nil {
+		return conflicts, errors.New(500, fmt.Sprintf("Error comparing online indices with snapshots list: %s", err))
+	}
+	restoring := jobStore.ActiveIndices(jobTypeRestore)
+
+	for _, indice := range indices {
+		stored, tracked := restoreOptionsStore.Lookup(indice)
+		if !tracked {
+			continue
+		}
+		// Indices whose restore failed or that were deleted outside of esio are restored again.
+		if stringInList(indiceStatus.Pending, indice) && !stringInList(restoring, indice) {
+			continue
+		}
+		if !sameRestoreOptions(stored, opts) {
+			conflicts = append(conflicts, indice)
 		}
 	}
 
-	return deleting, nil
+	return conflicts, nil
 }
 
 func stringInList(list []string, a string) bool {
@@ -357,26 +634,78 @@ func concat(old1, old2 []string) []string {
 	return newslice
 }
 
-func parseTimeRange(startInput int64, endInput int64) (time.Time, time.Time, error) {
-	utc, _ := time.LoadLocation("UTC")
-	start := time.Unix(startInput, 0)
-	end := time.Unix(endInput, 0)
+// Loads the IANA timezone with the given name.
+func parseTimezone(name string) (*time.Location, error) {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.New(400, fmt.Sprintf("Invalid timezone: %s", name))
+	}
+	return loc, nil
+}
+
+// Parses the start and end of a range into times in the given location.
+// See parseTime for the accepted formats, both are relative to the same now.
+func parseTimeRange(startInput string, endInput string, loc *time.Location) (time.Time, time.Time, error) {
+	now := time.Now().In(loc)
 
 	// Parse the start time
-	if startInput < 0 {
-		return start, end, errors.New(400, "Start time must be greater than 0")
+	start, err := parseTime(startInput, now)
+	if err != nil {
+		return start, start, errors.New(400, fmt.Sprintf("Invalid start time: %s", err))
 	}
-	start = start.In(utc)
 
 	// Parse the end time
-	if endInput < 0 {
-		return start, end, errors.New(400, "End time must be greater than 0")
+	end, err := parseTime(endInput, now)
+	if err != nil {
+		return start, end, errors.New(400, fmt.Sprintf("Invalid end time: %s", err))
 	}
-	end = end.In(utc)
 
 	// Time range must be valid
-	if startInput >= endInput {
+	if !start.Before(end) {
 		return start, end, errors.New(400, "Start time must be less than end time")
 	}
 	return start, end, nil
 }
+
+var relativeTimeRegexp = regexp.MustCompile(`^now(([+-])([0-9]+)([smhdw]))?$`)
+
+// Parses a unix timestamp, RFC3339 time, date like 2016-04-07 or a time relative to now like now, now-7d or now-12h.
+// Dates are midnight in the location of now, relative days and weeks are calendar days in that location.
+func parseTime(input string, now time.Time) (time.Time, error) {
+	if unix, err := strconv.ParseInt(input, 10, 64); err == nil {
+		if unix < 0 {
+			return now, fmt.Errorf("'%s', unix timestamp must be greater than 0", input)
+		}
+		return time.Unix(unix, 0).In(now.Location()), nil
+	}
+
+	if m := relativeTimeRegexp.FindStringSubmatch(input); m != nil {
+		if m[1] == "" {
+			return now, nil
+		}
+		n, err := strconv.Atoi(m[3])
+		if err != nil {
+			return now, fmt.Errorf("'%s', offset is out of range", input)
+		}
+		if m[2] == "-" {
+			n = -n
+		}
+		switch m[4] {
+			case "s": return now.Add(time.Duration(n) * time.Second), nil
+			case "m": return now.Add(time.Duration(n) * time.Minute), nil
+			case "h": return now.Add(time.Duration(n) * time.Hour), nil
+			case "d": return now.AddDate(0,0,n), nil
+			case "w": return now.AddDate(0,0,7*n), nil
+		}
+	}
+
+	if t, err := time.Parse(time.RFC3339, input); err == nil {
+		return t.In(now.Location()), nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", input, now.Location()); err == nil {
+		return t, nil
+	}
+
+	return now, fmt.Errorf("'%s', must be a unix timestamp, RFC3339 time like 2016-04-07T12:00:00Z, date like 2016-04-07 or relative time like now-7d", input)
+}
@@ -0,0 +1,411 @@
+package restapi
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	errors "github.com/go-openapi/errors"
+)
+
+func TestMakeIndexListFromRange(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Error loading timezone: %s", err)
+	}
+
+	date := func(loc *time.Location, year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name        string
+		start       time.Time
+		end         time.Time
+		resolution  string
+		repoPattern string
+		want        []string
+	}{
+		{
+			name:  "day range crossing midnight",
+			start: date(time.UTC, 2016, 4, 7, 23, 0), end: date(time.UTC, 2016, 4, 8, 1, 0),
+			resolution: "day", repoPattern: "test/snap/test-v1-%Y-%m-%d",
+			want: []string{"test/snap/test-v1-2016-04-07", "test/snap/test-v1-2016-04-08"},
+		},
+		{
+			name:  "day range ending on a boundary",
+			start: date(time.UTC, 2016, 1, 31, 12, 0), end: date(time.UTC, 2016, 2, 1, 0, 0),
+			resolution: "day", repoPattern: "test/snap/test-v1-%Y-%m-%d",
+			want: []string{"test/snap/test-v1-2016-01-31", "test/snap/test-v1-2016-02-01"},
+		},
+		{
+			name:  "month from the last day of January",
+			start: date(time.UTC, 2023, 1, 31, 23, 59), end: date(time.UTC, 2023, 3, 1, 0, 0),
+			resolution: "month", repoPattern: "test/snap/test-v1-%Y-%m",
+			want: []string{"test/snap/test-v1-2023-01", "test/snap/test-v1-2023-02", "test/snap/test-v1-2023-03"},
+		},
+		{
+			name:  "month starting on a boundary",
+			start: date(time.UTC, 2024, 2, 1, 0, 0), end: date(time.UTC, 2024, 2, 29, 23, 59),
+			resolution: "month", repoPattern: "test/snap/test-v1-%Y-%m",
+			want: []string{"test/snap/test-v1-2024-02"},
+		},
+		{
+			name:  "day over Feb 29 of a leap year",
+			start: date(time.UTC, 2024, 2, 28, 6, 0), end: date(time.UTC, 2024, 3, 1, 6, 0),
+			resolution: "day", repoPattern: "test/snap/test-v1-%Y-%m-%d",
+			want: []string{"test/snap/test-v1-2024-02-28", "test/snap/test-v1-2024-02-29", "test/snap/test-v1-2024-03-01"},
+		},
+		{
+			name:  "day over the end of February of a common year",
+			start: date(time.UTC, 2023, 2, 28, 6, 0), end: date(time.UTC, 2023, 3, 1, 6, 0),
+			resolution: "day", repoPattern: "test/snap/test-v1-%Y-%m-%d",
+			want: []string{"test/snap/test-v1-2023-02-28", "test/snap/test-v1-2023-03-01"},
+		},
+		{
+			name:  "day of year at the end of a leap year",
+			start: date(time.UTC, 2024, 12, 30, 0, 0), end: date(time.UTC, 2025, 1, 1, 0, 0),
+			resolution: "day", repoPattern: "test/snap/test-v1-%Y_%j",
+			want: []string{"test/snap/test-v1-2024_365", "test/snap/test-v1-2024_366", "test/snap/test-v1-2025_001"},
+		},
+		{
+			name:  "day of year at the end of a common year",
+			start: date(time.UTC, 2023, 12, 30, 0, 0), end: date(time.UTC, 2024, 1, 1, 0, 0),
+			resolution: "day", repoPattern: "test/snap/test-v1-%Y_%j",
+			want: []string{"test/snap/test-v1-2023_364", "test/snap/test-v1-2023_365", "test/snap/test-v1-2024_001"},
+		},
+		{
+			name:  "week over Feb 29",
+			start: date(time.UTC, 2024, 2, 29, 12, 0), end: date(time.UTC, 2024, 3, 5, 0, 0),
+			resolution: "week", repoPattern: "test/snap/test-v1-%Y-%m-%d",
+			want: []string{"test/snap/test-v1-2024-02-26", "test/snap/test-v1-2024-03-04"},
+		},
+		{
+			name:  "year from Feb 29",
+			start: date(time.UTC, 2024, 2, 29, 0, 0), end: date(time.UTC, 2025, 2, 28, 0, 0),
+			resolution: "year", repoPattern: "test/snap/test-v1-%Y",
+			want: []string{"test/snap/test-v1-2024", "test/snap/test-v1-2025"},
+		},
+		{
+			name:  "hour over the start of DST",
+			start: date(newYork, 2024, 3, 10, 0, 30), end: date(newYork, 2024, 3, 10, 3, 30),
+			resolution: "hour", repoPattern: "test/snap/test-v1-%Y-%m-%dT%H",
+			want: []string{"test/snap/test-v1-2024-03-10T00", "test/snap/test-v1-2024-03-10T01", "test/snap/test-v1-2024-03-10T03"},
+		},
+		{
+			name:  "hour over the end of DST",
+			start: date(newYork, 2024, 11, 3, 0, 30), end: date(newYork, 2024, 11, 3, 0, 30).Add(3 * time.Hour),
+			resolution: "hour", repoPattern: "test/snap/test-v1-%Y-%m-%dT%H",
+			want: []string{"test/snap/test-v1-2024-11-03T00", "test/snap/test-v1-2024-11-03T01", "test/snap/test-v1-2024-11-03T02"},
+		},
+		{
+			name:  "day over the start of DST",
+			start: date(newYork, 2024, 3, 9, 12, 0), end: date(newYork, 2024, 3, 11, 0, 0),
+			resolution: "day", repoPattern: "test/snap/test-v1-%Y-%m-%d",
+			want: []string{"test/snap/test-v1-2024-03-09", "test/snap/test-v1-2024-03-10", "test/snap/test-v1-2024-03-11"},
+		},
+		{
+			name:  "duration over the start of DST",
+			start: date(newYork, 2024, 3, 10, 0, 0), end: date(newYork, 2024, 3, 10, 12, 0),
+			resolution: "6h", repoPattern: "test/snap/test-v1-%Y-%m-%dT%H",
+			want: []string{"test/snap/test-v1-2024-03-10T00", "test/snap/test-v1-2024-03-10T06", "test/snap/test-v1-2024-03-10T12"},
+		},
+		{
+			name:  "duration over the end of DST",
+			start: date(newYork, 2024, 11, 3, 0, 0), end: date(newYork, 2024, 11, 3, 12, 0),
+			resolution: "6h", repoPattern: "test/snap/test-v1-%Y-%m-%dT%H",
+			want: []string{"test/snap/test-v1-2024-11-03T00", "test/snap/test-v1-2024-11-03T06", "test/snap/test-v1-2024-11-03T12"},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := makeIndexListFromRange(tt.start, tt.end, tt.resolution, tt.repoPattern)
+		if err != nil {
+			t.Errorf("%s: makeIndexListFromRange() error: %s", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: makeIndexListFromRange() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseResolution(t *testing.T) {
+	for _, resolution := range []string{"hour", "day", "week", "month", "year", "6h", "90m"} {
+		if _, err := parseResolution(resolution); err != nil {
+			t.Errorf("parseResolution(%q) error: %s", resolution, err)
+		}
+	}
+	for _, resolution := range []string{"", "days", "30s", "-6h", "0h"} {
+		if _, err := parseResolution(resolution); err == nil {
+			t.Errorf("parseResolution(%q) returned no error", resolution)
+		}
+	}
+
+	// Every time in an interval floors to its start, which is where the step from the previous interval lands.
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Error loading timezone: %s", err)
+	}
+	tests := []struct {
+		resolution string
+		t          time.Time
+		want       time.Time
+	}{
+		{"day", time.Date(2024, 2, 29, 23, 59, 0, 0, time.UTC), time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"week", time.Date(2024, 3, 3, 12, 0, 0, 0, time.UTC), time.Date(2024, 2, 26, 0, 0, 0, 0, time.UTC)},
+		{"month", time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"day", time.Date(2024, 3, 10, 23, 0, 0, 0, newYork), time.Date(2024, 3, 10, 0, 0, 0, 0, newYork)},
+		{"6h", time.Date(2024, 3, 10, 8, 0, 0, 0, newYork), time.Date(2024, 3, 10, 6, 0, 0, 0, newYork)},
+	}
+	for _, tt := range tests {
+		res, _ := parseResolution(tt.resolution)
+		if got := res.floor(tt.t); !got.Equal(tt.want) {
+			t.Errorf("%s: floor(%s) = %s, want %s", tt.resolution, tt.t, got, tt.want)
+		}
+	}
+
+	res, _ := parseResolution("month")
+	if got, want := res.step(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("month: step() = %s, want %s", got, want)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Error loading timezone: %s", err)
+	}
+	now := time.Date(2024, 3, 12, 15, 30, 0, 0, newYork)
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"1460073600", time.Date(2016, 4, 8, 0, 0, 0, 0, time.UTC)},
+		{"2016-04-07T12:00:00Z", time.Date(2016, 4, 7, 12, 0, 0, 0, time.UTC)},
+		{"2016-04-07T12:00:00-04:00", time.Date(2016, 4, 7, 16, 0, 0, 0, time.UTC)},
+		{"2016-04-07", time.Date(2016, 4, 7, 0, 0, 0, 0, newYork)},
+		{"now", now},
+		{"now-30s", now.Add(-30 * time.Second)},
+		{"now+15m", now.Add(15 * time.Minute)},
+		{"now-12h", now.Add(-12 * time.Hour)},
+		// Days are calendar days, the start of DST on Mar 10 does not shift the time of day.
+		{"now-7d", time.Date(2024, 3, 5, 15, 30, 0, 0, newYork)},
+		{"now-1w", time.Date(2024, 3, 5, 15, 30, 0, 0, newYork)},
+	}
+	for _, tt := range tests {
+		got, err := parseTime(tt.input, now)
+		if err != nil {
+			t.Errorf("parseTime(%q) error: %s", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) || got.Location() != newYork {
+			t.Errorf("parseTime(%q) = %s, want %s", tt.input, got, tt.want.In(newYork))
+		}
+	}
+
+	for _, input := range []string{"", "-1", "yesterday", "now-7", "now-d", "now-7y", "now - 7d", "2016-13-01", "2016-04-07 12:00", "now-99999999999999999999d"} {
+		if _, err := parseTime(input, now); err == nil {
+			t.Errorf("parseTime(%q) returned no error", input)
+		}
+	}
+}
+
+func TestParseTimeRange(t *testing.T) {
+	tests := []struct {
+		start string
+		end   string
+		valid bool
+	}{
+		{"2016-04-07", "2016-04-08", true},
+		{"1460073600", "2016-04-08T12:00:00Z", true},
+		{"now-7d", "now", true},
+		{"2016-04-08", "2016-04-07", false},
+		{"now", "now", false},
+		{"now", "now-1h", false},
+		{"yesterday", "now", false},
+		{"now-1d", "tomorrow", false},
+	}
+	for _, tt := range tests {
+		start, end, err := parseTimeRange(tt.start, tt.end, time.UTC)
+		if !tt.valid {
+			if err == nil {
+				t.Errorf("parseTimeRange(%q, %q) returned no error", tt.start, tt.end)
+			} else if e, ok := err.(errors.Error); !ok || e.Code() != 400 {
+				t.Errorf("parseTimeRange(%q, %q) error = %v, want a 400", tt.start, tt.end, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseTimeRange(%q, %q) error: %s", tt.start, tt.end, err)
+			continue
+		}
+		if !start.Before(end) || start.Location() != time.UTC {
+			t.Errorf("parseTimeRange(%q, %q) = %s, %s", tt.start, tt.end, start, end)
+		}
+	}
+}
+
+// Queues the given repo/snap/index paths for restore with the given options and a job, as POST does.
+func queueRestore(values []string, opts RestoreOptions) {
+	restoreOptionsStore.Set(values, opts)
+	jobStore.Create(jobTypeRestore, 0, 0, "day", "logs/nightly-1/logs-%Y", values, values)
+	for _, value := range values {
+		restoreQueue.Push(&Node{value})
+	}
+}
+
+func TestRestoreIndices(t *testing.T) {
+	f := newFakeBackend()
+	f.addSnapshot("logs", "nightly-1", snapshotSuccess, 1000, 10, "logs-a", "logs-b")
+	f.addSnapshot("logs", "nightly-2", snapshotSuccess, 2000, 10, "logs-c")
+	defer useFakeBackend(f, &Config{})()
+
+	values := []string{"logs/nightly-1/logs-a", "logs/nightly-1/logs-b"}
+	queueRestore(values, RestoreOptions{Rename: Rename{Pattern: "(.+)", Replacement: "restored-$1"}})
+	queueRestore([]string{"logs/nightly-2/logs-c"}, RestoreOptions{})
+
+	// The indices of one snapshot are restored together.
+	restoreIndices(restoreQueue.PopGroup(diskGuard.Ready, sameRestore))
+
+	if want := []string{"logs/nightly-1: logs-a,logs-b"}; !reflect.DeepEqual(f.restores, want) {
+		t.Errorf("restores = %v, want %v", f.restores, want)
+	}
+	if got, want := f.names(), []string{"restored-logs-a", "restored-logs-b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("indices on the cluster = %v, want %v", got, want)
+	}
+	if got, want := accessLog.LeastRecent(), values; !reflect.DeepEqual(got, want) {
+		t.Errorf("access log = %v, want %v", got, want)
+	}
+	if got, want := restoreQueue.Snapshot(), []string{"logs/nightly-2/logs-c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("restore queue = %v, want %v", got, want)
+	}
+	for _, job := range jobStore.List() {
+		if stringInList(job.Indices, values[0]) && job.State != jobCompleted {
+			t.Errorf("job state = %s, want %s, errors: %v", job.State, jobCompleted, job.Errors)
+		}
+	}
+
+	// A failed restore fails the job and stops tracking the indices.
+	f.restoreErr = fmt.Errorf("snapshot is in use")
+	restoreIndices(restoreQueue.PopGroup(diskGuard.Ready, sameRestore))
+
+	if _, tracked := restoreOptionsStore.Lookup("logs/nightly-2/logs-c"); tracked {
+		t.Errorf("restore options of a failed restore are still tracked")
+	}
+	for _, job := range jobStore.List() {
+		if stringInList(job.Indices, "logs/nightly-2/logs-c") && job.State != jobFailed {
+			t.Errorf("job state of a failed restore = %s, want %s", job.State, jobFailed)
+		}
+	}
+}
+
+func TestMakeIndexStatus(t *testing.T) {
+	f := newFakeBackend()
+	f.addSnapshot("logs", "nightly-1", snapshotSuccess, 1000, 10, "logs-a", "logs-b", "logs-c", "logs-d", "logs-e", "logs-f", "logs-g", "logs-h")
+	defer useFakeBackend(f, &Config{})()
+
+	rename := RestoreOptions{Rename: Rename{Pattern: "logs-(.+)", Replacement: "restored-$1"}}
+	restoreOptionsStore.Set([]string{"logs/nightly-1/logs-a"}, rename)
+	f.addIndex("restored-a", "green", 10)
+	f.addIndex("logs-b", "red", 10)
+	restoreQueue.Push(&Node{"logs/nightly-1/logs-c"})
+	restoreQueue.Push(&Node{"logs/nightly-1/logs-d"})
+	diskGuard.Defer([]string{"logs/nightly-1/logs-d"}, "disk is full")
+	f.addIndex("logs-e", "yellow", 10)
+	deleteQueue.Push(&Node{"logs/nightly-1/logs-e"})
+	// Restored outside of esio under a name given by the request's rename.
+	f.addIndex("restored-g", "green", 10)
+	// Taken off the queue by a restore worker and not on the cluster yet.
+	jobStore.Create(jobTypeRestore, 0, 0, "day", "logs/nightly-1/logs-%Y", []string{"logs/nightly-1/logs-h"}, []string{"logs/nightly-1/logs-h"})
+
+	indices := []string{"logs/nightly-1/logs-a", "logs/nightly-1/logs-b", "logs/nightly-1/logs-c", "logs/nightly-1/logs-d", "logs/nightly-1/logs-e", "logs/nightly-1/logs-f", "logs/nightly-1/logs-g", "logs/nightly-1/logs-h"}
+
+	tests := []struct {
+		name   string
+		rename *Rename
+		want   map[string][]string
+	}{
+		{"tracked names", nil, map[string][]string{
+			"ready":     {"logs/nightly-1/logs-a", "logs/nightly-1/logs-e"},
+			"restoring": {"logs/nightly-1/logs-b", "logs/nightly-1/logs-c", "logs/nightly-1/logs-h"},
+			"deferred":  {"logs/nightly-1/logs-d"},
+			"deleting":  {"logs/nightly-1/logs-e"},
+			"pending":   {"logs/nightly-1/logs-f", "logs/nightly-1/logs-g"},
+		}},
+		{"rename of untracked indices", &rename.Rename, map[string][]string{
+			"ready":     {"logs/nightly-1/logs-a", "logs/nightly-1/logs-g"},
+			"restoring": {"logs/nightly-1/logs-c", "logs/nightly-1/logs-h"},
+			"deferred":  {"logs/nightly-1/logs-d"},
+			"deleting":  {"logs/nightly-1/logs-e"},
+			"pending":   {"logs/nightly-1/logs-b", "logs/nightly-1/logs-f"},
+		}},
+	}
+
+	for _, tt := range tests {
+		status, err := makeIndexStatus(context.Background(), indices, tt.rename)
+		if err != nil {
+			t.Fatalf("%s: makeIndexStatus() error: %s", tt.name, err)
+		}
+		got := map[string][]string{
+			"ready":     sorted(status.Ready),
+			"restoring": sorted(status.Restoring),
+			"deferred":  sorted(status.Deferred),
+			"deleting":  sorted(status.Deleting),
+			"pending":   sorted(status.Pending),
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: makeIndexStatus() = %v, want %v", tt.name, got, tt.want)
+		}
+		if reason := status.DeferredReasons["logs/nightly-1/logs-d"]; reason != "disk is full" {
+			t.Errorf("%s: deferred reason = %q, want %q", tt.name, reason, "disk is full")
+		}
+		if snapshot := status.Snapshots["logs/nightly-1/logs-a"]; snapshot != "nightly-1" {
+			t.Errorf("%s: snapshot = %q, want nightly-1", tt.name, snapshot)
+		}
+	}
+}
+
+func TestDeletableIndices(t *testing.T) {
+	f := newFakeBackend()
+	defer useFakeBackend(f, &Config{})()
+
+	rename := Rename{Pattern: "(.+)", Replacement: "restored-$1"}
+	restoreOptionsStore.Set([]string{"logs/nightly-1/logs-a"}, RestoreOptions{Rename: rename})
+	restoreOptionsStore.Set([]string{"logs/nightly-1/logs-b", "logs/nightly-1/logs-c", "logs/nightly-1/logs-d"}, RestoreOptions{})
+	f.addIndex("restored-logs-a", "green", 10)
+	f.addIndex("logs-b", "green", 10)
+	f.addIndex("logs-c", "green", 10)
+	deleteQueue.Push(&Node{"logs/nightly-1/logs-c"})
+	// A live index with the name of an index in the range that esio did not restore.
+	f.addIndex("logs-e", "green", 10)
+
+	indices := []string{"logs/nightly-1/logs-a", "logs/nightly-1/logs-b", "logs/nightly-1/logs-c", "logs/nightly-1/logs-d", "logs/nightly-1/logs-e"}
+
+	tests := []struct {
+		rename *Rename
+		want   []string
+	}{
+		{nil, []string{"logs/nightly-1/logs-a", "logs/nightly-1/logs-b"}},
+		{&rename, []string{"logs/nightly-1/logs-a"}},
+	}
+	for _, tt := range tests {
+		got, err := deletableIndices(context.Background(), indices, tt.rename)
+		if err != nil {
+			t.Fatalf("deletableIndices(%v) error: %s", tt.rename, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("deletableIndices(%v) = %v, want %v", tt.rename, got, tt.want)
+		}
+	}
+}
+
+func sorted(list []string) []string {
+	s := append(make([]string, 0, len(list)), list...)
+	sort.Strings(s)
+	return s
+}
@@ -0,0 +1,285 @@
+package restapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// AccessLog records when each index restored by esio was last requested with a GET or POST.
+// Only indices in the log are ever evicted.
+type AccessLog struct {
+	entries map[string]time.Time
+	path    string
+	mu      sync.Mutex
+}
+
+var accessLog *AccessLog
+
+// NewAccessLog returns an empty access log, entries are persisted to path if it is not empty.
+func NewAccessLog(path string) (*AccessLog, error) {
+	a := &AccessLog{entries: make(map[string]time.Time), path: path}
+
+	if path == "" {
+		return a, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("Error reading access log '%s': %s", path, err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &a.entries); err != nil {
+			return nil, fmt.Errorf("Error decoding access log '%s': %s", path, err)
+		}
+	}
+
+	return a, nil
+}
+
+// Touch records a request for each index in the log.
+func (a *AccessLog) Touch(indices []string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	changed := false
+	now := time.Now()
+	for _, indice := range indices {
+		if _, ok := a.entries[indice]; ok {
+			a.entries[indice] = now
+			changed = true
+		}
+	}
+	if changed {
+		a.save()
+	}
+}
+
+// Restored adds an index restored by esio to the log.
+func (a *AccessLog) Restored(indice string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.entries[indice] = time.Now()
+	a.save()
+}
+
+// Forget removes the given indices from the log.
+func (a *AccessLog) Forget(indices []string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	changed := false
+	for _, indice := range indices {
+		if _, ok := a.entries[indice]; ok {
+			delete(a.entries, indice)
+			changed = true
+		}
+	}
+	if changed {
+		a.save()
+	}
+}
+
+// LeastRecent returns the indices in the log, least recently requested first.
+func (a *AccessLog) LeastRecent() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	restored := make(indicesByAccess, 0, len(a.entries))
+	for indice, last := range a.entries {
+		restored = append(restored, indiceAccess{indice, last})
+	}
+	sort.Sort(restored)
+
+	list := make([]string, 0, len(restored))
+	for _, r := range restored {
+		list = append(list, r.indice)
+	}
+	return list
+}
+
+// save writes the log to its file, if one is configured. The caller must hold a.mu.
+func (a *AccessLog) save() {
+	if a.path == "" {
+		return
+	}
+
+	data, err := json.Marshal(a.entries)
+	if err == nil {
+		err = writeFileAtomic(a.path, data)
+	}
+	if err != nil {
+		log.Println(fmt.Sprintf("ERROR: could not write access log '%s': %s", a.path, err))
+	}
+}
+
+// RestoreBudget holds the share of the restored data budget reserved by each restore admitted by makeRoom,
+// keyed by repo/snap/index path, until the restore is done. Restores running at once can't both be admitted
+// against the same free space.
+type RestoreBudget struct {
+	reserved map[string]int64
+	mu       sync.Mutex
+}
+
+var restoreBudget = NewRestoreBudget()
+
+func NewRestoreBudget() *RestoreBudget {
+	return &RestoreBudget{reserved: make(map[string]int64)}
+}
+
+// Release returns the budget reserved for the given indices, once their restore is done or has failed.
+func (b *RestoreBudget) Release(values []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, value := range values {
+		delete(b.reserved, value)
+	}
+}
+
+type indiceAccess struct {
+	indice string
+	last   time.Time
+}
+
+type indicesByAccess []indiceAccess
+
+func (a indicesByAccess) Len() int      { return len(a) }
+func (a indicesByAccess) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a indicesByAccess) Less(i, j int) bool {
+	if !a[i].last.Equal(a[j].last) {
+		return a[i].last.Before(a[j].last)
+	}
+	return a[i].indice < a[j].indice
+}
+
+// Makes room for the restore of the given repo/snap/index paths from snapshot under the restored data
+// budget by queueing the least recently requested restored indices for delete. Indices in the requested
+// range of an active restore job are never evicted. The restore is deferred until the evicted indices are
+// gone from the cluster, returns false if it can't be admitted yet, an error is returned if the restore is
+// larger than the budget. An admitted restore reserves its share of the budget, which is given back with
+// restoreBudget.Release once the restore is done.
+func makeRoom(snapshot string, values []string) (bool, error) {
+	limits := currentConfig().Limits
+	if limits.MaxRestoredBytes == 0 && limits.MaxRestoredIndices == 0 {
+		return true, nil
+	}
+
+	onlineIndices, err := backend.CatIndices(context.Background())
+	if err != nil {
+		diskGuard.Defer(values, fmt.Sprintf("Could not get restored indices: %s", err))
+		return false, nil
+	}
+	online := make(map[string]int64)
+	for _, i := range onlineIndices {
+		size, _ := strconv.ParseInt(i.StoreSize, 10, 64)
+		online[i.Index] = size
+	}
+
+	var needBytes int64
+	sizes := make(map[string]int64)
+	if limits.MaxRestoredBytes > 0 {
+		sizes, err = backend.SnapshotIndexSizes(context.Background(), snapshot)
+		if err != nil {
+			diskGuard.Defer(values, fmt.Sprintf("Could not get snapshot index sizes: %s", err))
+			return false, nil
+		}
+		for _, value := range values {
+			needBytes += sizes[path.Base(value)]
+		}
+	}
+	needIndices := len(values)
+
+	if limits.MaxRestoredBytes > 0 && needBytes > limits.MaxRestoredBytes {
+		return false, fmt.Errorf("restore of %d bytes is larger than the restored data budget of %d bytes", needBytes, limits.MaxRestoredBytes)
+	}
+	if limits.MaxRestoredIndices > 0 && needIndices > limits.MaxRestoredIndices {
+		return false, fmt.Errorf("restore of %d indices is larger than the restored data budget of %d indices", needIndices, limits.MaxRestoredIndices)
+	}
+
+	restoreBudget.mu.Lock()
+	defer restoreBudget.mu.Unlock()
+
+	// Restored data on the cluster and reserved by other restores, indices queued for delete count until
+	// they are gone.
+	var usedBytes, deletingBytes int64
+	usedIndices, deletingIndices := 0, 0
+	for indice, size := range restoreBudget.reserved {
+		if stringInList(values, indice) {
+			continue
+		}
+		usedBytes += size
+		usedIndices++
+	}
+
+	deleting := deleteQueue.Snapshot()
+	candidates := make([]string, 0)
+	for _, indice := range accessLog.LeastRecent() {
+		size, ok := online[restoredName(indice)]
+		if _, reserved := restoreBudget.reserved[indice]; !ok || reserved || stringInList(values, indice) {
+			continue
+		}
+		usedBytes += size
+		usedIndices++
+		if stringInList(deleting, indice) {
+			deletingBytes += size
+			deletingIndices++
+			continue
+		}
+		candidates = append(candidates, indice)
+	}
+
+	overBudget := func(usedBytes int64, usedIndices int) bool {
+		return (limits.MaxRestoredBytes > 0 && usedBytes+needBytes > limits.MaxRestoredBytes) ||
+			(limits.MaxRestoredIndices > 0 && usedIndices+needIndices > limits.MaxRestoredIndices)
+	}
+
+	if !overBudget(usedBytes, usedIndices) {
+		for _, value := range values {
+			restoreBudget.reserved[value] = sizes[path.Base(value)]
+		}
+		return true, nil
+	}
+
+	// Budget left once the indices already queued for delete and the evicted ones are gone.
+	freeBytes := usedBytes - deletingBytes
+	freeIndices := usedIndices - deletingIndices
+
+	protected := jobStore.ActiveRequested(jobTypeRestore)
+	evict := make([]string, 0)
+	for _, indice := range candidates {
+		if !overBudget(freeBytes, freeIndices) {
+			break
+		}
+		if stringInList(protected, indice) {
+			continue
+		}
+		evict = append(evict, indice)
+		freeBytes -= online[restoredName(indice)]
+		freeIndices--
+	}
+
+	if overBudget(freeBytes, freeIndices) {
+		diskGuard.Defer(values, fmt.Sprintf("Restore of %d indices, %d bytes, does not fit in the restored data budget, every restored index that could be evicted is part of an active restore", needIndices, needBytes))
+		return false, nil
+	}
+
+	for _, indice := range evict {
+		log.Println(fmt.Sprintf("Evicting least recently requested index to make room for restore from snapshot: %s, index: %s", snapshot, indice))
+		deleteQueue.Push(&Node{indice})
+	}
+	leaseStore.Release(evict)
+
+	// The restore is admitted once the delete worker has freed the space.
+	diskGuard.Defer(values, fmt.Sprintf("Restore of %d indices, %d bytes, is waiting for %d evicted indices to be deleted", needIndices, needBytes, deletingIndices+len(evict)))
+	return false, nil
+}
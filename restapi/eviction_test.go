@@ -0,0 +1,145 @@
+package restapi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Puts restored indices on the fake cluster and in the access log, least recently requested first.
+func restoreForEviction(f *fakeBackend, size int64, values ...string) {
+	last := time.Now().Add(-time.Hour)
+	for i, value := range values {
+		f.addIndex(restoredName(value), "green", size)
+		accessLog.entries[value] = last.Add(time.Duration(i) * time.Minute)
+	}
+}
+
+func TestMakeRoom(t *testing.T) {
+	f := newFakeBackend()
+	f.addSnapshot("logs", "nightly-2", snapshotSuccess, 2000, 10, "logs-c", "logs-d")
+	defer useFakeBackend(f, &Config{Limits: LimitsConfig{MaxRestoredIndices: 3}})()
+
+	restoreForEviction(f, 10, "logs/nightly-1/logs-a", "logs/nightly-1/logs-b")
+	values := []string{"logs/nightly-2/logs-c", "logs/nightly-2/logs-d"}
+
+	// The least recently requested index is evicted and the restore waits for it to be deleted.
+	admitted, err := makeRoom("logs/nightly-2", values)
+	if err != nil || admitted {
+		t.Fatalf("makeRoom() = %v, %v, want deferred", admitted, err)
+	}
+	if got, want := deleteQueue.Snapshot(), []string{"logs/nightly-1/logs-a"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("evicted %v, want %v", got, want)
+	}
+	if reason, _ := diskGuard.Deferred(values[0]); !strings.Contains(reason, "waiting for 1 evicted indices") {
+		t.Errorf("deferral reason = %q, want waiting for 1 evicted index", reason)
+	}
+
+	// Indices queued for delete are not evicted again.
+	if admitted, err := makeRoom("logs/nightly-2", values); err != nil || admitted {
+		t.Fatalf("makeRoom() before the delete = %v, %v, want deferred", admitted, err)
+	}
+	if got := deleteQueue.Len(); got != 1 {
+		t.Fatalf("%d indices queued for delete, want 1", got)
+	}
+
+	deleteIndex(deleteQueue.Pop())
+	if admitted, err := makeRoom("logs/nightly-2", values); err != nil || !admitted {
+		t.Fatalf("makeRoom() after the delete = %v, %v, want admitted", admitted, err)
+	}
+	if got, want := f.names(), []string{"logs-b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("indices on the cluster = %v, want %v", got, want)
+	}
+
+	// A restore larger than the budget can never be admitted.
+	large := []string{"logs/nightly-2/logs-c", "logs/nightly-2/logs-d", "logs/nightly-2/logs-e", "logs/nightly-2/logs-f"}
+	if _, err := makeRoom("logs/nightly-2", large); err == nil {
+		t.Errorf("makeRoom() of a restore larger than the budget returned no error")
+	}
+}
+
+func TestMakeRoomEvictionOrder(t *testing.T) {
+	f := newFakeBackend()
+	f.addSnapshot("logs", "nightly-2", snapshotSuccess, 2000, 30, "logs-e")
+	defer useFakeBackend(f, &Config{Limits: LimitsConfig{MaxRestoredBytes: 100}})()
+
+	// 40 bytes have to go, the two least recently requested indices are evicted.
+	restoreForEviction(f, 25, "logs/nightly-1/logs-a", "logs/nightly-1/logs-b", "logs/nightly-1/logs-c", "logs/nightly-1/logs-d")
+	accessLog.Touch([]string{"logs/nightly-1/logs-a"})
+
+	if admitted, err := makeRoom("logs/nightly-2", []string{"logs/nightly-2/logs-e"}); err != nil || admitted {
+		t.Fatalf("makeRoom() = %v, %v, want deferred", admitted, err)
+	}
+	if got, want := deleteQueue.Snapshot(), []string{"logs/nightly-1/logs-b", "logs/nightly-1/logs-c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("evicted %v, want %v", got, want)
+	}
+}
+
+func TestMakeRoomProtectsActiveJobs(t *testing.T) {
+	f := newFakeBackend()
+	f.addSnapshot("logs", "nightly-2", snapshotSuccess, 2000, 10, "logs-d")
+	defer useFakeBackend(f, &Config{Limits: LimitsConfig{MaxRestoredIndices: 3}})()
+
+	restoreForEviction(f, 10, "logs/nightly-1/logs-a", "logs/nightly-1/logs-b", "logs/nightly-1/logs-c")
+	values := []string{"logs/nightly-2/logs-d"}
+
+	// logs-a was already restored when the job asked for it, it is in the job's range but not queued.
+	jobStore.Create(jobTypeRestore, 0, 0, "day", "logs/nightly-*/logs-%Y", []string{"logs/nightly-1/logs-a", "logs/nightly-2/logs-d"}, values)
+
+	if admitted, err := makeRoom("logs/nightly-2", values); err != nil || admitted {
+		t.Fatalf("makeRoom() = %v, %v, want deferred", admitted, err)
+	}
+	if got, want := deleteQueue.Snapshot(), []string{"logs/nightly-1/logs-b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("evicted %v, want %v", got, want)
+	}
+
+	// With every restored index in the range of an active job nothing can be evicted.
+	deleteIndex(deleteQueue.Pop())
+	restoreForEviction(f, 10, "logs/nightly-1/logs-e")
+	jobStore.Create(jobTypeRestore, 0, 0, "day", "logs/nightly-*/logs-%Y", []string{"logs/nightly-1/logs-c", "logs/nightly-1/logs-e"}, []string{"logs/nightly-1/logs-e"})
+	if admitted, err := makeRoom("logs/nightly-2", values); err != nil || admitted {
+		t.Fatalf("makeRoom() with every index protected = %v, %v, want deferred", admitted, err)
+	}
+	if got := deleteQueue.Len(); got != 0 {
+		t.Errorf("%d indices evicted, want 0", got)
+	}
+}
+
+func TestMakeRoomConcurrentRestores(t *testing.T) {
+	f := newFakeBackend()
+	f.addSnapshot("logs", "nightly-2", snapshotSuccess, 2000, 10, "logs-c")
+	f.addSnapshot("logs", "nightly-3", snapshotSuccess, 3000, 10, "logs-d", "logs-e")
+	defer useFakeBackend(f, &Config{Limits: LimitsConfig{MaxRestoredBytes: 30}})()
+
+	restoreForEviction(f, 10, "logs/nightly-1/logs-a")
+
+	// The first restore fits and reserves its share of the budget.
+	first := []string{"logs/nightly-2/logs-c"}
+	if admitted, err := makeRoom("logs/nightly-2", first); err != nil || !admitted {
+		t.Fatalf("makeRoom() of the first restore = %v, %v, want admitted", admitted, err)
+	}
+
+	// The second restore only fits once logs-a is evicted, the index of the first restore is never evicted
+	// even once it is on the cluster.
+	f.addIndex("logs-c", "green", 10)
+	accessLog.Restored("logs/nightly-2/logs-c")
+	accessLog.entries["logs/nightly-2/logs-c"] = time.Now().Add(-2 * time.Hour)
+	second := []string{"logs/nightly-3/logs-d", "logs/nightly-3/logs-e"}
+	if admitted, err := makeRoom("logs/nightly-3", second); err != nil || admitted {
+		t.Fatalf("makeRoom() of the second restore = %v, %v, want deferred", admitted, err)
+	}
+	if got, want := deleteQueue.Snapshot(), []string{"logs/nightly-1/logs-a"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("evicted %v, want %v", got, want)
+	}
+
+	// Once logs-a is deleted and the first restore is done the second restore fits.
+	deleteIndex(deleteQueue.Pop())
+	restoreBudget.Release(first)
+	if admitted, err := makeRoom("logs/nightly-3", second); err != nil || !admitted {
+		t.Fatalf("makeRoom() after the delete = %v, %v, want admitted", admitted, err)
+	}
+	if got := len(restoreBudget.reserved); got != 2 {
+		t.Errorf("%d indices reserved, want 2", got)
+	}
+}
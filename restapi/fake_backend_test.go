@@ -0,0 +1,266 @@
+package restapi
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fakeBackend is an in-memory cluster for tests of the handlers and workers. Indices restored from a
+// snapshot come up green with the size the snapshot gives them and recover right away.
+type fakeBackend struct {
+	// Indices on the cluster, keyed by name.
+	indices map[string]CatIndex
+	// Snapshots of each repo.
+	snapshots map[string][]Snapshot
+	// Size of each index of a snapshot, keyed by repo/snap and index name.
+	sizes map[string]map[string]int64
+	// Indices, by name, that are missing from the recovery status.
+	unrecovered map[string]bool
+	// Indices, by name, whose recovery makes no progress.
+	stalled   map[string]bool
+	aliases   map[string][]string
+	diskTotal int64
+	diskAvail int64
+	// Returned by Restore when set.
+	restoreErr error
+
+	// Calls made to the fake.
+	restores      []string
+	deletes       []string
+	listSnapshots int
+	mu            sync.Mutex
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{
+		indices:     make(map[string]CatIndex),
+		snapshots:   make(map[string][]Snapshot),
+		sizes:       make(map[string]map[string]int64),
+		unrecovered: make(map[string]bool),
+		stalled:     make(map[string]bool),
+		aliases:     make(map[string][]string),
+		diskTotal:   1000,
+		diskAvail:   1000,
+	}
+}
+
+// Replaces the package level backend, config, queues and stores with empty ones using the fake and
+// returns a func that puts the previous ones back.
+func useFakeBackend(f *fakeBackend, c *Config) func() {
+	prevBackend, prevConfig := backend, currentConfig()
+	prevRestoreQueue, prevDeleteQueue, prevRestorePool := restoreQueue, deleteQueue, restorePool
+	prevDiskGuard, prevJobStore, prevLeaseStore := diskGuard, jobStore, leaseStore
+	prevAccessLog, prevRestoreOptionsStore := accessLog, restoreOptionsStore
+	prevSnapshotCatalog, prevRecoveryTracker, prevAliasManager := snapshotCatalog, recoveryTracker, aliasManager
+	prevRestoreBudget := restoreBudget
+
+	if c.timeout == 0 {
+		c.timeout = 5 * time.Second
+	}
+	if c.restoreTimeout == 0 {
+		c.restoreTimeout = time.Minute
+	}
+	if c.Limits.DiskWatermark == 0 {
+		c.Limits.DiskWatermark = 100
+	}
+	setConfig(c)
+
+	backend = f
+	restoreQueue = NewQueue(1)
+	deleteQueue = NewQueue(1)
+	restorePool = NewRestorePool(1)
+	diskGuard = NewDiskGuard(c.Limits.DiskWatermark)
+	jobStore, _ = NewJobStore("")
+	leaseStore, _ = NewLeaseStore("")
+	accessLog, _ = NewAccessLog("")
+	restoreOptionsStore, _ = NewRestoreOptionsStore("")
+	snapshotCatalog = NewSnapshotCatalog()
+	recoveryTracker = NewRecoveryTracker()
+	aliasManager = NewAliasManager()
+	restoreBudget = NewRestoreBudget()
+
+	return func() {
+		backend = prevBackend
+		setConfig(prevConfig)
+		restoreQueue, deleteQueue, restorePool = prevRestoreQueue, prevDeleteQueue, prevRestorePool
+		diskGuard, jobStore, leaseStore = prevDiskGuard, prevJobStore, prevLeaseStore
+		accessLog, restoreOptionsStore = prevAccessLog, prevRestoreOptionsStore
+		snapshotCatalog, recoveryTracker, aliasManager = prevSnapshotCatalog, prevRecoveryTracker, prevAliasManager
+		restoreBudget = prevRestoreBudget
+	}
+}
+
+// addSnapshot adds a snapshot of the given indices, each of the given size, to a repo.
+func (f *fakeBackend) addSnapshot(repo string, name string, state string, startMillis int64, size int64, indices ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.snapshots[repo] = append(f.snapshots[repo], Snapshot{Snapshot: name, Indices: indices, State: state, StartTimeInMillis: startMillis})
+	sizes := make(map[string]int64)
+	for _, index := range indices {
+		sizes[index] = size
+	}
+	f.sizes[path.Join(repo, name)] = sizes
+}
+
+// addIndex puts an index on the cluster.
+func (f *fakeBackend) addIndex(name string, health string, size int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.indices[name] = CatIndex{Health: health, Status: "open", Index: name, StoreSize: strconv.FormatInt(size, 10)}
+}
+
+// names returns the names of the indices on the cluster, sorted.
+func (f *fakeBackend) names() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	names := make([]string, 0, len(f.indices))
+	for name := range f.indices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (f *fakeBackend) Version(ctx context.Context) (ClusterVersion, error) {
+	return ClusterVersion{Distribution: distributionElasticsearch, Number: "7.17.0", Major: 7, Minor: 17}, nil
+}
+
+func (f *fakeBackend) ClusterHealth(ctx context.Context) (string, error) {
+	return "green", nil
+}
+
+func (f *fakeBackend) RepoExists(ctx context.Context, repo string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.snapshots[repo]; !ok {
+		return fmt.Errorf("repository [%s] missing", repo)
+	}
+	return nil
+}
+
+func (f *fakeBackend) ListSnapshots(ctx context.Context, name string) ([]Snapshot, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.listSnapshots++
+	repo, snap := name, ""
+	if i := strings.Index(name, "/"); i >= 0 {
+		repo, snap = name[:i], name[i+1:]
+	}
+	snapshots := make([]Snapshot, 0)
+	for _, s := range f.snapshots[repo] {
+		if snap == "" || s.Snapshot == snap {
+			snapshots = append(snapshots, s)
+		}
+	}
+	return snapshots, nil
+}
+
+func (f *fakeBackend) SnapshotIndexSizes(ctx context.Context, snapshot string) (map[string]int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sizes, ok := f.sizes[snapshot]
+	if !ok {
+		return nil, fmt.Errorf("snapshot [%s] missing", snapshot)
+	}
+	return sizes, nil
+}
+
+func (f *fakeBackend) Restore(ctx context.Context, snapshot string, indices []string, opts RestoreOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.restores = append(f.restores, fmt.Sprintf("%s: %s", snapshot, strings.Join(indices, ",")))
+	if f.restoreErr != nil {
+		return f.restoreErr
+	}
+	for _, index := range indices {
+		name := opts.Rename.apply(index)
+		f.indices[name] = CatIndex{Health: "green", Status: "open", Index: name, StoreSize: strconv.FormatInt(f.sizes[snapshot][index], 10)}
+	}
+	return nil
+}
+
+func (f *fakeBackend) RecoveryStatus(ctx context.Context, indices []string) (map[string][]ShardRecovery, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	recoveries := make(map[string][]ShardRecovery)
+	for _, index := range indices {
+		if _, ok := f.indices[index]; !ok || f.unrecovered[index] {
+			continue
+		}
+		shard := ShardRecovery{Type: "SNAPSHOT", Stage: "DONE", Primary: true}
+		shard.Index.Size.TotalInBytes = 10
+		shard.Index.Size.RecoveredInBytes = 10
+		if f.stalled[index] {
+			shard.Stage = "INDEX"
+			shard.Index.Size.RecoveredInBytes = 0
+		}
+		recoveries[index] = []ShardRecovery{shard}
+	}
+	return recoveries, nil
+}
+
+func (f *fakeBackend) CatIndices(ctx context.Context) ([]CatIndex, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	indices := make([]CatIndex, 0, len(f.indices))
+	for _, index := range f.indices {
+		indices = append(indices, index)
+	}
+	return indices, nil
+}
+
+func (f *fakeBackend) DeleteIndex(ctx context.Context, index string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.deletes = append(f.deletes, index)
+	if _, ok := f.indices[index]; !ok {
+		return fmt.Errorf("no such index [%s]", index)
+	}
+	delete(f.indices, index)
+	return nil
+}
+
+func (f *fakeBackend) PutAlias(ctx context.Context, index string, alias string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.aliases[index] = append(f.aliases[index], alias)
+	return nil
+}
+
+func (f *fakeBackend) DeleteAlias(ctx context.Context, index string, alias string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	kept := make([]string, 0)
+	for _, a := range f.aliases[index] {
+		if a != alias {
+			kept = append(kept, a)
+		}
+	}
+	f.aliases[index] = kept
+	return nil
+}
+
+func (f *fakeBackend) DiskUsage(ctx context.Context) (int64, int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.diskTotal, f.diskAvail, nil
+}
@@ -0,0 +1,381 @@
+package restapi
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	errors "github.com/go-openapi/errors"
+
+	"github.com/danisla/esio/models"
+)
+
+// Job types
+const (
+	jobTypeRestore = "restore"
+	jobTypeDelete  = "delete"
+)
+
+// Job states
+const (
+	jobQueued    = "queued"
+	jobRunning   = "running"
+	jobCompleted = "completed"
+	jobFailed    = "failed"
+	jobCancelled = "cancelled"
+)
+
+// Finished jobs are kept this long before being pruned.
+const jobRetention = 24 * time.Hour
+
+// JobStore records a job for every POST and DELETE request so clients can follow the work it queued.
+// Workers report progress per index, a job finishes when every index it queued has been processed.
+type JobStore struct {
+	jobs map[string]*models.Job
+	path string
+	mu   sync.Mutex
+}
+
+var jobStore *JobStore
+
+// NewJobStore returns an empty job store, jobs are persisted to path if it is not empty.
+func NewJobStore(path string) (*JobStore, error) {
+	s := &JobStore{jobs: make(map[string]*models.Job), path: path}
+
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("Error reading jobs file '%s': %s", path, err)
+	}
+	if len(data) > 0 {
+		var list []*models.Job
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, fmt.Errorf("Error decoding jobs file '%s': %s", path, err)
+		}
+		for _, job := range list {
+			s.jobs[job.ID] = job
+		}
+	}
+
+	return s, nil
+}
+
+// Create records a new job for the given request, the indices in its range and the indices it is about to
+// queue. A job that queued no indices is completed right away.
+func (s *JobStore) Create(jobType string, start int64, end int64, resolution string, repoPattern string, requested []string, indices []string) *models.Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().Unix()
+	job := &models.Job{
+		ID:          newJobID(),
+		Type:        jobType,
+		State:       jobQueued,
+		Start:       start,
+		End:         end,
+		Resolution:  resolution,
+		RepoPattern: repoPattern,
+		Requested:   append(make([]string, 0, len(requested)), requested...),
+		Indices:     append(make([]string, 0, len(indices)), indices...),
+		Completed:   make([]string, 0),
+		Errors:      make([]string, 0),
+		Created:     now,
+		Updated:     now,
+	}
+	if len(indices) == 0 {
+		job.State = jobCompleted
+	}
+
+	s.prune(now)
+	s.jobs[job.ID] = job
+	s.save()
+
+	return copyJob(job)
+}
+
+// Get returns a copy of the job with the given ID.
+func (s *JobStore) Get(id string) (*models.Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	return copyJob(job), true
+}
+
+// List returns copies of all jobs, oldest first.
+func (s *JobStore) List() []*models.Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]*models.Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		list = append(list, copyJob(job))
+	}
+	sort.Sort(jobsByCreated(list))
+	return list
+}
+
+// Started moves queued jobs of the given type that contain any of the indices to the running state.
+func (s *JobStore) Started(jobType string, indices []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := false
+	for _, job := range s.jobs {
+		if job.Type != jobType || job.State != jobQueued {
+			continue
+		}
+		for _, indice := range indices {
+			if isPending(job, indice) {
+				job.State = jobRunning
+				job.Updated = time.Now().Unix()
+				changed = true
+				break
+			}
+		}
+	}
+	if changed {
+		s.save()
+	}
+}
+
+// Finished records the outcome for an index on every active job of the given type waiting on it.
+func (s *JobStore) Finished(jobType string, indice string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := false
+	for _, job := range s.jobs {
+		if job.Type != jobType || !isActive(job) || !isPending(job, indice) {
+			continue
+		}
+
+		job.Completed = append(job.Completed, indice)
+		if err != nil {
+			job.Errors = append(job.Errors, fmt.Sprintf("%s: %s", indice, err))
+		}
+
+		job.State = jobRunning
+		if len(job.Completed) == len(job.Indices) {
+			job.State = jobCompleted
+			if len(job.Errors) > 0 {
+				job.State = jobFailed
+			}
+		}
+		job.Updated = time.Now().Unix()
+		changed = true
+	}
+	if changed {
+		s.save()
+	}
+}
+
+// ActiveIndices returns the indices of all active jobs of the given type.
+func (s *JobStore) ActiveIndices(jobType string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	indices := make([]string, 0)
+	for _, job := range s.jobs {
+		if job.Type != jobType || !isActive(job) {
+			continue
+		}
+		for _, indice := range job.Indices {
+			if !stringInList(indices, indice) {
+				indices = append(indices, indice)
+			}
+		}
+	}
+	return indices
+}
+
+// PendingIndices returns the indices of all active jobs of the given type that have not been processed yet,
+// those still queued and those a worker has taken off the queue.
+func (s *JobStore) PendingIndices(jobType string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	indices := make([]string, 0)
+	for _, job := range s.jobs {
+		if job.Type != jobType || !isActive(job) {
+			continue
+		}
+		for _, indice := range job.Indices {
+			if isPending(job, indice) && !stringInList(indices, indice) {
+				indices = append(indices, indice)
+			}
+		}
+	}
+	return indices
+}
+
+// ActiveRequested returns the indices in the requested range of all active jobs of the given type, also
+// those that did not need to be queued.
+func (s *JobStore) ActiveRequested(jobType string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	indices := make([]string, 0)
+	for _, job := range s.jobs {
+		if job.Type != jobType || !isActive(job) {
+			continue
+		}
+		// Jobs recorded before the requested range was kept only have their queued indices.
+		for _, list := range [][]string{job.Requested, job.Indices} {
+			for _, indice := range list {
+				if !stringInList(indices, indice) {
+					indices = append(indices, indice)
+				}
+			}
+		}
+	}
+	return indices
+}
+
+// Cancel marks an active job as cancelled. It returns the cancelled job along with the indices
+// that are no longer wanted by any other active job and can be taken off the queue.
+func (s *JobStore) Cancel(id string) (*models.Job, []string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, nil, errors.New(404, fmt.Sprintf("Job not found: %s", id))
+	}
+	if !isActive(job) {
+		return copyJob(job), nil, errors.New(409, fmt.Sprintf("Job '%s' is already %s", id, job.State))
+	}
+
+	job.State = jobCancelled
+	job.Updated = time.Now().Unix()
+
+	unwanted := make([]string, 0)
+	for _, indice := range job.Indices {
+		if !isPending(job, indice) {
+			continue
+		}
+		wanted := false
+		for _, other := range s.jobs {
+			if other.Type == job.Type && isActive(other) && isPending(other, indice) {
+				wanted = true
+				break
+			}
+		}
+		if !wanted {
+			unwanted = append(unwanted, indice)
+		}
+	}
+
+	s.save()
+
+	return copyJob(job), unwanted, nil
+}
+
+// prune removes finished jobs older than jobRetention, the caller must hold s.mu.
+func (s *JobStore) prune(now int64) {
+	for id, job := range s.jobs {
+		if !isActive(job) && now-job.Updated > int64(jobRetention/time.Second) {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// save writes all jobs to the jobs file, if one is configured. The caller must hold s.mu.
+func (s *JobStore) save() {
+	if s.path == "" {
+		return
+	}
+
+	list := make([]*models.Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		list = append(list, job)
+	}
+	sort.Sort(jobsByCreated(list))
+
+	data, err := json.Marshal(list)
+	if err == nil {
+		err = writeFileAtomic(s.path, data)
+	}
+	if err != nil {
+		log.Println(fmt.Sprintf("ERROR: could not write jobs file '%s': %s", s.path, err))
+	}
+}
+
+// Cancels the job with the given ID and removes its indices that are still waiting from the queue.
+func cancelJob(id string) (*models.Job, error) {
+	job, unwanted, err := jobStore.Cancel(id)
+	if err != nil {
+		return job, err
+	}
+
+	queue := restoreQueue
+	if job.Type == jobTypeDelete {
+		queue = deleteQueue
+	}
+
+	if job.Type == jobTypeRestore {
+		diskGuard.Forget(unwanted)
+	}
+
+	for _, indice := range unwanted {
+		if queue.Remove(indice) {
+			log.Println(fmt.Sprintf("Removed index from %s queue for cancelled job %s: %s", job.Type, job.ID, indice))
+		}
+	}
+
+	return job, nil
+}
+
+// Returns the URL of the given job.
+func jobLocation(job *models.Job) string {
+	return fmt.Sprintf("/jobs/%s", job.ID)
+}
+
+func newJobID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+func isActive(job *models.Job) bool {
+	return job.State == jobQueued || job.State == jobRunning
+}
+
+func isPending(job *models.Job, indice string) bool {
+	return stringInList(job.Indices, indice) && !stringInList(job.Completed, indice)
+}
+
+func copyJob(job *models.Job) *models.Job {
+	c := *job
+	c.Requested = append(make([]string, 0, len(job.Requested)), job.Requested...)
+	c.Indices = append(make([]string, 0, len(job.Indices)), job.Indices...)
+	c.Completed = append(make([]string, 0, len(job.Completed)), job.Completed...)
+	c.Errors = append(make([]string, 0, len(job.Errors)), job.Errors...)
+	return &c
+}
+
+type jobsByCreated []*models.Job
+
+func (a jobsByCreated) Len() int      { return len(a) }
+func (a jobsByCreated) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a jobsByCreated) Less(i, j int) bool {
+	if a[i].Created != a[j].Created {
+		return a[i].Created < a[j].Created
+	}
+	return a[i].ID < a[j].ID
+}
@@ -0,0 +1,212 @@
+package restapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// How often the reaper looks for restored indices with an expired lease.
+const reapInterval = 60 * time.Second
+
+// Lease is the time a restored index is kept on the cluster before it is queued for delete.
+type Lease struct {
+	TTL     time.Duration `json:"ttl"`
+	Expires time.Time     `json:"expires"`
+}
+
+// LeaseStore tracks the lease of every restored index that has a TTL, keyed by repo/snap/index path.
+type LeaseStore struct {
+	leases map[string]Lease
+	path   string
+	mu     sync.Mutex
+}
+
+var leaseStore *LeaseStore
+
+// NewLeaseStore returns an empty lease store, leases are persisted to path if it is not empty.
+func NewLeaseStore(path string) (*LeaseStore, error) {
+	s := &LeaseStore{leases: make(map[string]Lease), path: path}
+
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("Error reading leases file '%s': %s", path, err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &s.leases); err != nil {
+			return nil, fmt.Errorf("Error decoding leases file '%s': %s", path, err)
+		}
+	}
+
+	return s, nil
+}
+
+// Grant sets the lease of each index to ttl from now. An existing lease is only ever extended.
+func (s *LeaseStore) Grant(indices []string, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expires := time.Now().Add(ttl)
+	for _, indice := range indices {
+		lease, ok := s.leases[indice]
+		if ok && lease.Expires.After(expires) {
+			continue
+		}
+		s.leases[indice] = Lease{TTL: ttl, Expires: expires}
+	}
+	s.save()
+}
+
+// Touch extends the lease of each index that has one by its TTL from now.
+func (s *LeaseStore) Touch(indices []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := false
+	now := time.Now()
+	for _, indice := range indices {
+		lease, ok := s.leases[indice]
+		if !ok {
+			continue
+		}
+		if expires := now.Add(lease.TTL); expires.After(lease.Expires) {
+			lease.Expires = expires
+			s.leases[indice] = lease
+			changed = true
+		}
+	}
+	if changed {
+		s.save()
+	}
+}
+
+// Release removes the lease of each index.
+func (s *LeaseStore) Release(indices []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := false
+	for _, indice := range indices {
+		if _, ok := s.leases[indice]; ok {
+			delete(s.leases, indice)
+			changed = true
+		}
+	}
+	if changed {
+		s.save()
+	}
+}
+
+// Expired returns the indices whose lease has expired.
+func (s *LeaseStore) Expired() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expired := make([]string, 0)
+	now := time.Now()
+	for indice, lease := range s.leases {
+		if now.After(lease.Expires) {
+			expired = append(expired, indice)
+		}
+	}
+	return expired
+}
+
+// save writes all leases to the leases file, if one is configured. The caller must hold s.mu.
+func (s *LeaseStore) save() {
+	if s.path == "" {
+		return
+	}
+
+	data, err := json.Marshal(s.leases)
+	if err == nil {
+		err = writeFileAtomic(s.path, data)
+	}
+	if err != nil {
+		log.Println(fmt.Sprintf("ERROR: could not write leases file '%s': %s", s.path, err))
+	}
+}
+
+// Starts or extends the lease of the indices of a range that esio restored or queued for restore, indices
+// without a TTL are kept until deleted. Indices on the cluster that esio did not restore are never leased.
+func grantLeases(indices []string, ttl time.Duration) {
+	tracked := restoreOptionsStore.Tracked(indices)
+	if ttl > 0 {
+		leaseStore.Grant(tracked, ttl)
+	} else {
+		leaseStore.Touch(tracked)
+	}
+}
+
+// Queues restored indices with an expired lease for delete. Indices that are still restoring
+// keep their lease until they are ready, leases of indices esio is not tracking are dropped.
+func reapExpiredIndices() {
+	expired := leaseStore.Expired()
+	if len(expired) == 0 {
+		return
+	}
+
+	untracked := make([]string, 0)
+	for _, indice := range expired {
+		if _, tracked := restoreOptionsStore.Lookup(indice); !tracked {
+			untracked = append(untracked, indice)
+		}
+	}
+	if len(untracked) > 0 {
+		log.Println(fmt.Sprintf("Dropped expired leases of indices not restored by esio: %s", strings.Join(untracked, ",")))
+		leaseStore.Release(untracked)
+		expired = restoreOptionsStore.Tracked(expired)
+	}
+
+	indiceStatus, err := makeIndexStatus(context.Background(), expired, nil)
+	if err != nil {
+		log.Println(fmt.Sprintf("ERROR: could not get status of indices with expired lease: %s", err))
+		return
+	}
+
+	for _, indice := range expired {
+		if stringInList(indiceStatus.Restoring, indice) || stringInList(indiceStatus.Deleting, indice) {
+			continue
+		}
+
+		if stringInList(indiceStatus.Ready, indice) {
+			log.Println(fmt.Sprintf("Lease expired, index queued for delete: %s", indice))
+			deleteQueue.Push(&Node{indice})
+		}
+		leaseStore.Release([]string{indice})
+	}
+}
+
+// Parses a TTL like 48h, 90m or 7d. Days are not supported by time.ParseDuration so are handled here.
+func parseTTL(ttl string) (time.Duration, error) {
+	if strings.HasSuffix(ttl, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(ttl, "d"))
+		if err != nil {
+			return 0, fmt.Errorf("Invalid TTL: %s", ttl)
+		}
+		if days <= 0 {
+			return 0, fmt.Errorf("TTL must be greater than 0: %s", ttl)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(ttl)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid TTL: %s", ttl)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("TTL must be greater than 0: %s", ttl)
+	}
+	return d, nil
+}
@@ -0,0 +1,41 @@
+package restapi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGrantLeases(t *testing.T) {
+	f := newFakeBackend()
+	defer useFakeBackend(f, &Config{})()
+
+	// logs-a was restored outside of esio, logs-b was restored by esio and logs-c is queued for restore.
+	f.addIndex("logs-a", "green", 10)
+	f.addIndex("logs-b", "green", 10)
+	restoreOptionsStore.Set([]string{"logs/nightly-1/logs-b", "logs/nightly-1/logs-c"}, RestoreOptions{})
+	indices := []string{"logs/nightly-1/logs-a", "logs/nightly-1/logs-b", "logs/nightly-1/logs-c"}
+
+	grantLeases(indices, time.Hour)
+	leased := make([]string, 0)
+	for indice := range leaseStore.leases {
+		leased = append(leased, indice)
+	}
+	if want := []string{"logs/nightly-1/logs-b", "logs/nightly-1/logs-c"}; !reflect.DeepEqual(sorted(leased), want) {
+		t.Fatalf("leased %v, want %v", sorted(leased), want)
+	}
+
+	// A lease of an untracked index, like one persisted by an older version, is dropped without a delete.
+	expired := Lease{TTL: time.Hour, Expires: time.Now().Add(-time.Minute)}
+	for _, indice := range indices {
+		leaseStore.leases[indice] = expired
+	}
+	reapExpiredIndices()
+
+	if got, want := deleteQueue.Snapshot(), []string{"logs/nightly-1/logs-b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("queued for delete %v, want %v", got, want)
+	}
+	if _, ok := leaseStore.leases["logs/nightly-1/logs-a"]; ok {
+		t.Errorf("lease of an untracked index was kept")
+	}
+}
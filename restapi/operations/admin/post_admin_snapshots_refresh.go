@@ -0,0 +1,53 @@
+package admin
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	middleware "github.com/go-openapi/runtime/middleware"
+)
+
+// PostAdminSnapshotsRefreshHandlerFunc turns a function with the right signature into a post admin snapshots refresh handler
+type PostAdminSnapshotsRefreshHandlerFunc func(PostAdminSnapshotsRefreshParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn PostAdminSnapshotsRefreshHandlerFunc) Handle(params PostAdminSnapshotsRefreshParams) middleware.Responder {
+	return fn(params)
+}
+
+// PostAdminSnapshotsRefreshHandler interface for that can handle valid post admin snapshots refresh params
+type PostAdminSnapshotsRefreshHandler interface {
+	Handle(PostAdminSnapshotsRefreshParams) middleware.Responder
+}
+
+// NewPostAdminSnapshotsRefresh creates a new http.Handler for the post admin snapshots refresh operation
+func NewPostAdminSnapshotsRefresh(ctx *middleware.Context, handler PostAdminSnapshotsRefreshHandler) *PostAdminSnapshotsRefresh {
+	return &PostAdminSnapshotsRefresh{Context: ctx, Handler: handler}
+}
+
+/*PostAdminSnapshotsRefresh swagger:route POST /admin/snapshots/refresh admin postAdminSnapshotsRefresh
+
+Refreshes the snapshot catalog from the cluster instead of waiting for the next background refresh.
+
+*/
+type PostAdminSnapshotsRefresh struct {
+	Context *middleware.Context
+	Handler PostAdminSnapshotsRefreshHandler
+}
+
+func (o *PostAdminSnapshotsRefresh) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, _ := o.Context.RouteInfo(r)
+	var Params = NewPostAdminSnapshotsRefreshParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
@@ -0,0 +1,69 @@
+package admin
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware"
+
+	strfmt "github.com/go-openapi/strfmt"
+)
+
+// NewPostAdminSnapshotsRefreshParams creates a new PostAdminSnapshotsRefreshParams object
+// with the default values initialized.
+func NewPostAdminSnapshotsRefreshParams() PostAdminSnapshotsRefreshParams {
+	var ()
+	return PostAdminSnapshotsRefreshParams{}
+}
+
+// PostAdminSnapshotsRefreshParams contains all the bound params for the post admin snapshots refresh operation
+// typically these are obtained from a http.Request
+//
+// swagger:parameters PostAdminSnapshotsRefresh
+type PostAdminSnapshotsRefreshParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request
+
+	/*Snapshot repo to refresh, every repo in the catalog is refreshed if not set.
+	  In: query
+	*/
+	Repo *string
+}
+
+// BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
+// for simple values it will use straight method calls
+func (o *PostAdminSnapshotsRefreshParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
+	var res []error
+	o.HTTPRequest = r
+
+	qs := runtime.Values(r.URL.Query())
+
+	qRepo, qhkRepo, _ := qs.GetOK("repo")
+	if err := o.bindRepo(qRepo, qhkRepo, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (o *PostAdminSnapshotsRefreshParams) bindRepo(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.Repo = &raw
+
+	return nil
+}
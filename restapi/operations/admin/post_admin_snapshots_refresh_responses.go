@@ -0,0 +1,107 @@
+package admin
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/danisla/esio/models"
+)
+
+/*PostAdminSnapshotsRefreshOK Repos of the snapshot catalog after the refresh.
+
+swagger:response postAdminSnapshotsRefreshOK
+*/
+type PostAdminSnapshotsRefreshOK struct {
+
+	// In: body
+	Payload []*models.SnapshotCatalogRepo `json:"body,omitempty"`
+}
+
+// NewPostAdminSnapshotsRefreshOK creates PostAdminSnapshotsRefreshOK with default headers values
+func NewPostAdminSnapshotsRefreshOK() *PostAdminSnapshotsRefreshOK {
+	return &PostAdminSnapshotsRefreshOK{}
+}
+
+// WithPayload adds the payload to the post admin snapshots refresh o k response
+func (o *PostAdminSnapshotsRefreshOK) WithPayload(payload []*models.SnapshotCatalogRepo) *PostAdminSnapshotsRefreshOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the post admin snapshots refresh o k response
+func (o *PostAdminSnapshotsRefreshOK) SetPayload(payload []*models.SnapshotCatalogRepo) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *PostAdminSnapshotsRefreshOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	payload := o.Payload
+	if payload == nil {
+		payload = make([]*models.SnapshotCatalogRepo, 0, 50)
+	}
+
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
+
+/*PostAdminSnapshotsRefreshDefault Unexpected error
+
+swagger:response postAdminSnapshotsRefreshDefault
+*/
+type PostAdminSnapshotsRefreshDefault struct {
+	_statusCode int
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewPostAdminSnapshotsRefreshDefault creates PostAdminSnapshotsRefreshDefault with default headers values
+func NewPostAdminSnapshotsRefreshDefault(code int) *PostAdminSnapshotsRefreshDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &PostAdminSnapshotsRefreshDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the post admin snapshots refresh default response
+func (o *PostAdminSnapshotsRefreshDefault) WithStatusCode(code int) *PostAdminSnapshotsRefreshDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the post admin snapshots refresh default response
+func (o *PostAdminSnapshotsRefreshDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the post admin snapshots refresh default response
+func (o *PostAdminSnapshotsRefreshDefault) WithPayload(payload *models.Error) *PostAdminSnapshotsRefreshDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the post admin snapshots refresh default response
+func (o *PostAdminSnapshotsRefreshDefault) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *PostAdminSnapshotsRefreshDefault) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
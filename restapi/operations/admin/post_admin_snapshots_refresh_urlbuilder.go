@@ -0,0 +1,80 @@
+package admin
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"errors"
+	"net/url"
+)
+
+// PostAdminSnapshotsRefreshURL generates an URL for the post admin snapshots refresh operation
+type PostAdminSnapshotsRefreshURL struct {
+	Repo *string
+
+	// avoid unkeyed usage
+	_ struct{}
+}
+
+// Build a url path and query string
+func (o *PostAdminSnapshotsRefreshURL) Build() (*url.URL, error) {
+	var result url.URL
+
+	var _path = "/admin/snapshots/refresh"
+
+	result.Path = _path
+
+	qs := make(url.Values)
+
+	var repo string
+	if o.Repo != nil {
+		repo = *o.Repo
+	}
+	if repo != "" {
+		qs.Set("repo", repo)
+	}
+
+	result.RawQuery = qs.Encode()
+
+	return &result, nil
+}
+
+// Must is a helper function to panic when the url builder returns an error
+func (o *PostAdminSnapshotsRefreshURL) Must(u *url.URL, err error) *url.URL {
+	if err != nil {
+		panic(err)
+	}
+	if u == nil {
+		panic("url can't be nil")
+	}
+	return u
+}
+
+// String returns the string representation of the path with query string
+func (o *PostAdminSnapshotsRefreshURL) String() string {
+	return o.Must(o.Build()).String()
+}
+
+// BuildFull builds a full url with scheme, host, path and query string
+func (o *PostAdminSnapshotsRefreshURL) BuildFull(scheme, host string) (*url.URL, error) {
+	if scheme == "" {
+		return nil, errors.New("scheme is required for a full url on PostAdminSnapshotsRefreshURL")
+	}
+	if host == "" {
+		return nil, errors.New("host is required for a full url on PostAdminSnapshotsRefreshURL")
+	}
+
+	base, err := o.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	base.Scheme = scheme
+	base.Host = host
+	return base, nil
+}
+
+// StringFull returns the string representation of a complete url
+func (o *PostAdminSnapshotsRefreshURL) StringFull(scheme, host string) string {
+	return o.Must(o.BuildFull(scheme, host)).String()
+}
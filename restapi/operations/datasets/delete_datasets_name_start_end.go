@@ -0,0 +1,53 @@
+package datasets
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	middleware "github.com/go-openapi/runtime/middleware"
+)
+
+// DeleteDatasetsNameStartEndHandlerFunc turns a function with the right signature into a delete datasets name start end handler
+type DeleteDatasetsNameStartEndHandlerFunc func(DeleteDatasetsNameStartEndParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn DeleteDatasetsNameStartEndHandlerFunc) Handle(params DeleteDatasetsNameStartEndParams) middleware.Responder {
+	return fn(params)
+}
+
+// DeleteDatasetsNameStartEndHandler interface for that can handle valid delete datasets name start end params
+type DeleteDatasetsNameStartEndHandler interface {
+	Handle(DeleteDatasetsNameStartEndParams) middleware.Responder
+}
+
+// NewDeleteDatasetsNameStartEnd creates a new http.Handler for the delete datasets name start end operation
+func NewDeleteDatasetsNameStartEnd(ctx *middleware.Context, handler DeleteDatasetsNameStartEndHandler) *DeleteDatasetsNameStartEnd {
+	return &DeleteDatasetsNameStartEnd{Context: ctx, Handler: handler}
+}
+
+/*DeleteDatasetsNameStartEnd swagger:route DELETE /datasets/{name}/{start}/{end} datasets deleteDatasetsNameStartEnd
+
+DeleteDatasetsNameStartEnd delete datasets name start end API
+
+*/
+type DeleteDatasetsNameStartEnd struct {
+	Context *middleware.Context
+	Handler DeleteDatasetsNameStartEndHandler
+}
+
+func (o *DeleteDatasetsNameStartEnd) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, _ := o.Context.RouteInfo(r)
+	var Params = NewDeleteDatasetsNameStartEndParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
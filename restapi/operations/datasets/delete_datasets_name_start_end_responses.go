@@ -0,0 +1,252 @@
+package datasets
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/danisla/esio/models"
+)
+
+/*DeleteDatasetsNameStartEndOK All indices in [start,end] range are no longer online.
+
+swagger:response deleteDatasetsNameStartEndOK
+*/
+type DeleteDatasetsNameStartEndOK struct {
+
+	/*URL of the job created for this request.
+	 */
+	Location string `json:"Location"`
+
+	// In: body
+	Payload *models.IndiceStatus `json:"body,omitempty"`
+}
+
+// NewDeleteDatasetsNameStartEndOK creates DeleteDatasetsNameStartEndOK with default headers values
+func NewDeleteDatasetsNameStartEndOK() *DeleteDatasetsNameStartEndOK {
+	return &DeleteDatasetsNameStartEndOK{}
+}
+
+// WithLocation adds the location to the delete datasets name start end o k response
+func (o *DeleteDatasetsNameStartEndOK) WithLocation(location string) *DeleteDatasetsNameStartEndOK {
+	o.Location = location
+	return o
+}
+
+// SetLocation sets the location to the delete datasets name start end o k response
+func (o *DeleteDatasetsNameStartEndOK) SetLocation(location string) {
+	o.Location = location
+}
+
+// WithPayload adds the payload to the delete datasets name start end o k response
+func (o *DeleteDatasetsNameStartEndOK) WithPayload(payload *models.IndiceStatus) *DeleteDatasetsNameStartEndOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the delete datasets name start end o k response
+func (o *DeleteDatasetsNameStartEndOK) SetPayload(payload *models.IndiceStatus) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *DeleteDatasetsNameStartEndOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	// response header Location
+	rw.Header().Set("Location", fmt.Sprintf("%v", o.Location))
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*DeleteDatasetsNameStartEndAccepted Index delete started
+
+swagger:response deleteDatasetsNameStartEndAccepted
+*/
+type DeleteDatasetsNameStartEndAccepted struct {
+
+	/*URL of the job created for this request.
+	 */
+	Location string `json:"Location"`
+
+	// In: body
+	Payload *models.IndiceStatus `json:"body,omitempty"`
+}
+
+// NewDeleteDatasetsNameStartEndAccepted creates DeleteDatasetsNameStartEndAccepted with default headers values
+func NewDeleteDatasetsNameStartEndAccepted() *DeleteDatasetsNameStartEndAccepted {
+	return &DeleteDatasetsNameStartEndAccepted{}
+}
+
+// WithLocation adds the location to the delete datasets name start end accepted response
+func (o *DeleteDatasetsNameStartEndAccepted) WithLocation(location string) *DeleteDatasetsNameStartEndAccepted {
+	o.Location = location
+	return o
+}
+
+// SetLocation sets the location to the delete datasets name start end accepted response
+func (o *DeleteDatasetsNameStartEndAccepted) SetLocation(location string) {
+	o.Location = location
+}
+
+// WithPayload adds the payload to the delete datasets name start end accepted response
+func (o *DeleteDatasetsNameStartEndAccepted) WithPayload(payload *models.IndiceStatus) *DeleteDatasetsNameStartEndAccepted {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the delete datasets name start end accepted response
+func (o *DeleteDatasetsNameStartEndAccepted) SetPayload(payload *models.IndiceStatus) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *DeleteDatasetsNameStartEndAccepted) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	// response header Location
+	rw.Header().Set("Location", fmt.Sprintf("%v", o.Location))
+
+	rw.WriteHeader(202)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*DeleteDatasetsNameStartEndBadRequest invalid time range provided or dataset not found
+
+swagger:response deleteDatasetsNameStartEndBadRequest
+*/
+type DeleteDatasetsNameStartEndBadRequest struct {
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewDeleteDatasetsNameStartEndBadRequest creates DeleteDatasetsNameStartEndBadRequest with default headers values
+func NewDeleteDatasetsNameStartEndBadRequest() *DeleteDatasetsNameStartEndBadRequest {
+	return &DeleteDatasetsNameStartEndBadRequest{}
+}
+
+// WithPayload adds the payload to the delete datasets name start end bad request response
+func (o *DeleteDatasetsNameStartEndBadRequest) WithPayload(payload *models.Error) *DeleteDatasetsNameStartEndBadRequest {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the delete datasets name start end bad request response
+func (o *DeleteDatasetsNameStartEndBadRequest) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *DeleteDatasetsNameStartEndBadRequest) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(400)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*DeleteDatasetsNameStartEndRequestRangeNotSatisfiable Not all indices in given [start,end] range were found to delete or were actively being restored.
+
+swagger:response deleteDatasetsNameStartEndRequestRangeNotSatisfiable
+*/
+type DeleteDatasetsNameStartEndRequestRangeNotSatisfiable struct {
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewDeleteDatasetsNameStartEndRequestRangeNotSatisfiable creates DeleteDatasetsNameStartEndRequestRangeNotSatisfiable with default headers values
+func NewDeleteDatasetsNameStartEndRequestRangeNotSatisfiable() *DeleteDatasetsNameStartEndRequestRangeNotSatisfiable {
+	return &DeleteDatasetsNameStartEndRequestRangeNotSatisfiable{}
+}
+
+// WithPayload adds the payload to the delete datasets name start end request range not satisfiable response
+func (o *DeleteDatasetsNameStartEndRequestRangeNotSatisfiable) WithPayload(payload *models.Error) *DeleteDatasetsNameStartEndRequestRangeNotSatisfiable {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the delete datasets name start end request range not satisfiable response
+func (o *DeleteDatasetsNameStartEndRequestRangeNotSatisfiable) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *DeleteDatasetsNameStartEndRequestRangeNotSatisfiable) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(416)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*DeleteDatasetsNameStartEndDefault Unexpected error
+
+swagger:response deleteDatasetsNameStartEndDefault
+*/
+type DeleteDatasetsNameStartEndDefault struct {
+	_statusCode int
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewDeleteDatasetsNameStartEndDefault creates DeleteDatasetsNameStartEndDefault with default headers values
+func NewDeleteDatasetsNameStartEndDefault(code int) *DeleteDatasetsNameStartEndDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &DeleteDatasetsNameStartEndDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the delete datasets name start end default response
+func (o *DeleteDatasetsNameStartEndDefault) WithStatusCode(code int) *DeleteDatasetsNameStartEndDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the delete datasets name start end default response
+func (o *DeleteDatasetsNameStartEndDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the delete datasets name start end default response
+func (o *DeleteDatasetsNameStartEndDefault) WithPayload(payload *models.Error) *DeleteDatasetsNameStartEndDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the delete datasets name start end default response
+func (o *DeleteDatasetsNameStartEndDefault) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *DeleteDatasetsNameStartEndDefault) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package datasets
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// DeleteDatasetsNameStartEndURL generates an URL for the delete datasets name start end operation
+type DeleteDatasetsNameStartEndURL struct {
+	End   string
+	Name  string
+	Start string
+
+	// avoid unkeyed usage
+	_ struct{}
+}
+
+// Build a url path and query string
+func (o *DeleteDatasetsNameStartEndURL) Build() (*url.URL, error) {
+	var result url.URL
+
+	var _path = "/datasets/{name}/{start}/{end}"
+
+	end := o.End
+	if end != "" {
+		_path = strings.Replace(_path, "{end}", end, -1)
+	} else {
+		return nil, errors.New("End is required on DeleteDatasetsNameStartEndURL")
+	}
+	name := o.Name
+	if name != "" {
+		_path = strings.Replace(_path, "{name}", name, -1)
+	} else {
+		return nil, errors.New("Name is required on DeleteDatasetsNameStartEndURL")
+	}
+	start := o.Start
+	if start != "" {
+		_path = strings.Replace(_path, "{start}", start, -1)
+	} else {
+		return nil, errors.New("Start is required on DeleteDatasetsNameStartEndURL")
+	}
+	result.Path = _path
+
+	return &result, nil
+}
+
+// Must is a helper function to panic when the url builder returns an error
+func (o *DeleteDatasetsNameStartEndURL) Must(u *url.URL, err error) *url.URL {
+	if err != nil {
+		panic(err)
+	}
+	if u == nil {
+		panic("url can't be nil")
+	}
+	return u
+}
+
+// String returns the string representation of the path with query string
+func (o *DeleteDatasetsNameStartEndURL) String() string {
+	return o.Must(o.Build()).String()
+}
+
+// BuildFull builds a full url with scheme, host, path and query string
+func (o *DeleteDatasetsNameStartEndURL) BuildFull(scheme, host string) (*url.URL, error) {
+	if scheme == "" {
+		return nil, errors.New("scheme is required for a full url on DeleteDatasetsNameStartEndURL")
+	}
+	if host == "" {
+		return nil, errors.New("host is required for a full url on DeleteDatasetsNameStartEndURL")
+	}
+
+	base, err := o.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	base.Scheme = scheme
+	base.Host = host
+	return base, nil
+}
+
+// StringFull returns the string representation of a complete url
+func (o *DeleteDatasetsNameStartEndURL) StringFull(scheme, host string) string {
+	return o.Must(o.BuildFull(scheme, host)).String()
+}
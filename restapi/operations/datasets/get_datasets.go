@@ -0,0 +1,53 @@
+package datasets
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	middleware "github.com/go-openapi/runtime/middleware"
+)
+
+// GetDatasetsHandlerFunc turns a function with the right signature into a get datasets handler
+type GetDatasetsHandlerFunc func(GetDatasetsParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn GetDatasetsHandlerFunc) Handle(params GetDatasetsParams) middleware.Responder {
+	return fn(params)
+}
+
+// GetDatasetsHandler interface for that can handle valid get datasets params
+type GetDatasetsHandler interface {
+	Handle(GetDatasetsParams) middleware.Responder
+}
+
+// NewGetDatasets creates a new http.Handler for the get datasets operation
+func NewGetDatasets(ctx *middleware.Context, handler GetDatasetsHandler) *GetDatasets {
+	return &GetDatasets{Context: ctx, Handler: handler}
+}
+
+/*GetDatasets swagger:route GET /datasets datasets getDatasets
+
+GetDatasets get datasets API
+
+*/
+type GetDatasets struct {
+	Context *middleware.Context
+	Handler GetDatasetsHandler
+}
+
+func (o *GetDatasets) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, _ := o.Context.RouteInfo(r)
+	var Params = NewGetDatasetsParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
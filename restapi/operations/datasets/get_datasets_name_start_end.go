@@ -0,0 +1,53 @@
+package datasets
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	middleware "github.com/go-openapi/runtime/middleware"
+)
+
+// GetDatasetsNameStartEndHandlerFunc turns a function with the right signature into a get datasets name start end handler
+type GetDatasetsNameStartEndHandlerFunc func(GetDatasetsNameStartEndParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn GetDatasetsNameStartEndHandlerFunc) Handle(params GetDatasetsNameStartEndParams) middleware.Responder {
+	return fn(params)
+}
+
+// GetDatasetsNameStartEndHandler interface for that can handle valid get datasets name start end params
+type GetDatasetsNameStartEndHandler interface {
+	Handle(GetDatasetsNameStartEndParams) middleware.Responder
+}
+
+// NewGetDatasetsNameStartEnd creates a new http.Handler for the get datasets name start end operation
+func NewGetDatasetsNameStartEnd(ctx *middleware.Context, handler GetDatasetsNameStartEndHandler) *GetDatasetsNameStartEnd {
+	return &GetDatasetsNameStartEnd{Context: ctx, Handler: handler}
+}
+
+/*GetDatasetsNameStartEnd swagger:route GET /datasets/{name}/{start}/{end} datasets getDatasetsNameStartEnd
+
+GetDatasetsNameStartEnd get datasets name start end API
+
+*/
+type GetDatasetsNameStartEnd struct {
+	Context *middleware.Context
+	Handler GetDatasetsNameStartEndHandler
+}
+
+func (o *GetDatasetsNameStartEnd) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, _ := o.Context.RouteInfo(r)
+	var Params = NewGetDatasetsNameStartEndParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
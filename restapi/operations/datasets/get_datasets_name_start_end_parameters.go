@@ -0,0 +1,106 @@
+package datasets
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime/middleware"
+
+	strfmt "github.com/go-openapi/strfmt"
+)
+
+// NewGetDatasetsNameStartEndParams creates a new GetDatasetsNameStartEndParams object
+// with the default values initialized.
+func NewGetDatasetsNameStartEndParams() GetDatasetsNameStartEndParams {
+	var ()
+	return GetDatasetsNameStartEndParams{}
+}
+
+// GetDatasetsNameStartEndParams contains all the bound params for the get datasets name start end operation
+// typically these are obtained from a http.Request
+//
+// swagger:parameters GetDatasetsNameStartEnd
+type GetDatasetsNameStartEndParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request
+
+	/*End time, unix timestamp, RFC3339 time, date like 2016-04-07 or relative time like now
+	  Required: true
+	  In: path
+	*/
+	End string
+	/*Name of the dataset
+	  Required: true
+	  In: path
+	*/
+	Name string
+	/*Start time, unix timestamp, RFC3339 time, date like 2016-04-07 or relative time like now-7d
+	  Required: true
+	  In: path
+	*/
+	Start string
+}
+
+// BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
+// for simple values it will use straight method calls
+func (o *GetDatasetsNameStartEndParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
+	var res []error
+	o.HTTPRequest = r
+
+	rEnd, rhkEnd, _ := route.Params.GetOK("end")
+	if err := o.bindEnd(rEnd, rhkEnd, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	rName, rhkName, _ := route.Params.GetOK("name")
+	if err := o.bindName(rName, rhkName, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	rStart, rhkStart, _ := route.Params.GetOK("start")
+	if err := o.bindStart(rStart, rhkStart, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (o *GetDatasetsNameStartEndParams) bindEnd(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	o.End = raw
+
+	return nil
+}
+
+func (o *GetDatasetsNameStartEndParams) bindName(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	o.Name = raw
+
+	return nil
+}
+
+func (o *GetDatasetsNameStartEndParams) bindStart(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	o.Start = raw
+
+	return nil
+}
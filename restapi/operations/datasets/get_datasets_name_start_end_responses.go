@@ -0,0 +1,252 @@
+package datasets
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/danisla/esio/models"
+)
+
+/*GetDatasetsNameStartEndOK All indices in [start,end] range are availble and ready.
+
+swagger:response getDatasetsNameStartEndOK
+*/
+type GetDatasetsNameStartEndOK struct {
+
+	// In: body
+	Payload *models.IndiceStatus `json:"body,omitempty"`
+}
+
+// NewGetDatasetsNameStartEndOK creates GetDatasetsNameStartEndOK with default headers values
+func NewGetDatasetsNameStartEndOK() *GetDatasetsNameStartEndOK {
+	return &GetDatasetsNameStartEndOK{}
+}
+
+// WithPayload adds the payload to the get datasets name start end o k response
+func (o *GetDatasetsNameStartEndOK) WithPayload(payload *models.IndiceStatus) *GetDatasetsNameStartEndOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get datasets name start end o k response
+func (o *GetDatasetsNameStartEndOK) SetPayload(payload *models.IndiceStatus) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetDatasetsNameStartEndOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*GetDatasetsNameStartEndPartialContent Zero or more of the indices in the [start,end] range are available and ready.
+
+swagger:response getDatasetsNameStartEndPartialContent
+*/
+type GetDatasetsNameStartEndPartialContent struct {
+
+	// In: body
+	Payload *models.IndiceStatus `json:"body,omitempty"`
+}
+
+// NewGetDatasetsNameStartEndPartialContent creates GetDatasetsNameStartEndPartialContent with default headers values
+func NewGetDatasetsNameStartEndPartialContent() *GetDatasetsNameStartEndPartialContent {
+	return &GetDatasetsNameStartEndPartialContent{}
+}
+
+// WithPayload adds the payload to the get datasets name start end partial content response
+func (o *GetDatasetsNameStartEndPartialContent) WithPayload(payload *models.IndiceStatus) *GetDatasetsNameStartEndPartialContent {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get datasets name start end partial content response
+func (o *GetDatasetsNameStartEndPartialContent) SetPayload(payload *models.IndiceStatus) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetDatasetsNameStartEndPartialContent) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(206)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*GetDatasetsNameStartEndBadRequest invalid time range provided or dataset not found
+
+swagger:response getDatasetsNameStartEndBadRequest
+*/
+type GetDatasetsNameStartEndBadRequest struct {
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewGetDatasetsNameStartEndBadRequest creates GetDatasetsNameStartEndBadRequest with default headers values
+func NewGetDatasetsNameStartEndBadRequest() *GetDatasetsNameStartEndBadRequest {
+	return &GetDatasetsNameStartEndBadRequest{}
+}
+
+// WithPayload adds the payload to the get datasets name start end bad request response
+func (o *GetDatasetsNameStartEndBadRequest) WithPayload(payload *models.Error) *GetDatasetsNameStartEndBadRequest {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get datasets name start end bad request response
+func (o *GetDatasetsNameStartEndBadRequest) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetDatasetsNameStartEndBadRequest) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(400)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*GetDatasetsNameStartEndNotFound Indices in the [start,end] range are available for restore but not available.
+
+swagger:response getDatasetsNameStartEndNotFound
+*/
+type GetDatasetsNameStartEndNotFound struct {
+
+	// In: body
+	Payload *models.IndiceStatus `json:"body,omitempty"`
+}
+
+// NewGetDatasetsNameStartEndNotFound creates GetDatasetsNameStartEndNotFound with default headers values
+func NewGetDatasetsNameStartEndNotFound() *GetDatasetsNameStartEndNotFound {
+	return &GetDatasetsNameStartEndNotFound{}
+}
+
+// WithPayload adds the payload to the get datasets name start end not found response
+func (o *GetDatasetsNameStartEndNotFound) WithPayload(payload *models.IndiceStatus) *GetDatasetsNameStartEndNotFound {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get datasets name start end not found response
+func (o *GetDatasetsNameStartEndNotFound) SetPayload(payload *models.IndiceStatus) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetDatasetsNameStartEndNotFound) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(404)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*GetDatasetsNameStartEndRequestRangeNotSatisfiable No indices are available for restore in given [start,end] range.
+
+swagger:response getDatasetsNameStartEndRequestRangeNotSatisfiable
+*/
+type GetDatasetsNameStartEndRequestRangeNotSatisfiable struct {
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewGetDatasetsNameStartEndRequestRangeNotSatisfiable creates GetDatasetsNameStartEndRequestRangeNotSatisfiable with default headers values
+func NewGetDatasetsNameStartEndRequestRangeNotSatisfiable() *GetDatasetsNameStartEndRequestRangeNotSatisfiable {
+	return &GetDatasetsNameStartEndRequestRangeNotSatisfiable{}
+}
+
+// WithPayload adds the payload to the get datasets name start end request range not satisfiable response
+func (o *GetDatasetsNameStartEndRequestRangeNotSatisfiable) WithPayload(payload *models.Error) *GetDatasetsNameStartEndRequestRangeNotSatisfiable {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get datasets name start end request range not satisfiable response
+func (o *GetDatasetsNameStartEndRequestRangeNotSatisfiable) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetDatasetsNameStartEndRequestRangeNotSatisfiable) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(416)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*GetDatasetsNameStartEndDefault Unexpected error
+
+swagger:response getDatasetsNameStartEndDefault
+*/
+type GetDatasetsNameStartEndDefault struct {
+	_statusCode int
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewGetDatasetsNameStartEndDefault creates GetDatasetsNameStartEndDefault with default headers values
+func NewGetDatasetsNameStartEndDefault(code int) *GetDatasetsNameStartEndDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &GetDatasetsNameStartEndDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the get datasets name start end default response
+func (o *GetDatasetsNameStartEndDefault) WithStatusCode(code int) *GetDatasetsNameStartEndDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the get datasets name start end default response
+func (o *GetDatasetsNameStartEndDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the get datasets name start end default response
+func (o *GetDatasetsNameStartEndDefault) WithPayload(payload *models.Error) *GetDatasetsNameStartEndDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get datasets name start end default response
+func (o *GetDatasetsNameStartEndDefault) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetDatasetsNameStartEndDefault) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
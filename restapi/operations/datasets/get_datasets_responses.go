@@ -0,0 +1,107 @@
+package datasets
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/danisla/esio/models"
+)
+
+/*GetDatasetsOK List of configured datasets.
+
+swagger:response getDatasetsOK
+*/
+type GetDatasetsOK struct {
+
+	// In: body
+	Payload []*models.Dataset `json:"body,omitempty"`
+}
+
+// NewGetDatasetsOK creates GetDatasetsOK with default headers values
+func NewGetDatasetsOK() *GetDatasetsOK {
+	return &GetDatasetsOK{}
+}
+
+// WithPayload adds the payload to the get datasets o k response
+func (o *GetDatasetsOK) WithPayload(payload []*models.Dataset) *GetDatasetsOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get datasets o k response
+func (o *GetDatasetsOK) SetPayload(payload []*models.Dataset) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetDatasetsOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	payload := o.Payload
+	if payload == nil {
+		payload = make([]*models.Dataset, 0, 50)
+	}
+
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
+
+/*GetDatasetsDefault Unexpected error
+
+swagger:response getDatasetsDefault
+*/
+type GetDatasetsDefault struct {
+	_statusCode int
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewGetDatasetsDefault creates GetDatasetsDefault with default headers values
+func NewGetDatasetsDefault(code int) *GetDatasetsDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &GetDatasetsDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the get datasets default response
+func (o *GetDatasetsDefault) WithStatusCode(code int) *GetDatasetsDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the get datasets default response
+func (o *GetDatasetsDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the get datasets default response
+func (o *GetDatasetsDefault) WithPayload(payload *models.Error) *GetDatasetsDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get datasets default response
+func (o *GetDatasetsDefault) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetDatasetsDefault) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package datasets
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	middleware "github.com/go-openapi/runtime/middleware"
+)
+
+// PostDatasetsNameStartEndHandlerFunc turns a function with the right signature into a post datasets name start end handler
+type PostDatasetsNameStartEndHandlerFunc func(PostDatasetsNameStartEndParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn PostDatasetsNameStartEndHandlerFunc) Handle(params PostDatasetsNameStartEndParams) middleware.Responder {
+	return fn(params)
+}
+
+// PostDatasetsNameStartEndHandler interface for that can handle valid post datasets name start end params
+type PostDatasetsNameStartEndHandler interface {
+	Handle(PostDatasetsNameStartEndParams) middleware.Responder
+}
+
+// NewPostDatasetsNameStartEnd creates a new http.Handler for the post datasets name start end operation
+func NewPostDatasetsNameStartEnd(ctx *middleware.Context, handler PostDatasetsNameStartEndHandler) *PostDatasetsNameStartEnd {
+	return &PostDatasetsNameStartEnd{Context: ctx, Handler: handler}
+}
+
+/*PostDatasetsNameStartEnd swagger:route POST /datasets/{name}/{start}/{end} datasets postDatasetsNameStartEnd
+
+PostDatasetsNameStartEnd post datasets name start end API
+
+*/
+type PostDatasetsNameStartEnd struct {
+	Context *middleware.Context
+	Handler PostDatasetsNameStartEndHandler
+}
+
+func (o *PostDatasetsNameStartEnd) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, _ := o.Context.RouteInfo(r)
+	var Params = NewPostDatasetsNameStartEndParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
@@ -0,0 +1,256 @@
+package datasets
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+
+	strfmt "github.com/go-openapi/strfmt"
+)
+
+// NewPostDatasetsNameStartEndParams creates a new PostDatasetsNameStartEndParams object
+// with the default values initialized.
+func NewPostDatasetsNameStartEndParams() PostDatasetsNameStartEndParams {
+	var ()
+	return PostDatasetsNameStartEndParams{}
+}
+
+// PostDatasetsNameStartEndParams contains all the bound params for the post datasets name start end operation
+// typically these are obtained from a http.Request
+//
+// swagger:parameters PostDatasetsNameStartEnd
+type PostDatasetsNameStartEndParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request
+
+	/*Optional alias attached to the restored indices once they are green and detached before they are deleted, ex restored-logs. Overrides the alias of the dataset.
+	  In: query
+	*/
+	Alias *string
+	/*End time, unix timestamp, RFC3339 time, date like 2016-04-07 or relative time like now
+	  Required: true
+	  In: path
+	*/
+	End string
+	/*Optional comma separated index settings of the snapshot that are not restored, ex index.routing.allocation.*, must be URL encoded. Overrides the value of the dataset.
+	  In: query
+	*/
+	IgnoreIndexSettings *string
+	/*Optional, false to restore the indices without their aliases. Overrides the value of the dataset.
+	  In: query
+	*/
+	IncludeAliases *bool
+	/*Optional, true to also restore the cluster state of the snapshot. Overrides the value of the dataset.
+	  In: query
+	*/
+	IncludeGlobalState *bool
+	/*Optional index settings of the restored indices as a JSON object, ex {"number_of_replicas":0}, must be URL encoded. Overrides the value of the dataset.
+	  In: query
+	*/
+	IndexSettings *string
+	/*Name of the dataset
+	  Required: true
+	  In: path
+	*/
+	Name string
+	/*Start time, unix timestamp, RFC3339 time, date like 2016-04-07 or relative time like now-7d
+	  Required: true
+	  In: path
+	*/
+	Start string
+	/*Optional time to keep the restored indices before they are deleted, ex 48h or 7d. Overrides the TTL of the dataset.
+	  In: query
+	*/
+	TTL *string
+}
+
+// BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
+// for simple values it will use straight method calls
+func (o *PostDatasetsNameStartEndParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
+	var res []error
+	o.HTTPRequest = r
+
+	qs := runtime.Values(r.URL.Query())
+
+	qAlias, qhkAlias, _ := qs.GetOK("alias")
+	if err := o.bindAlias(qAlias, qhkAlias, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	rEnd, rhkEnd, _ := route.Params.GetOK("end")
+	if err := o.bindEnd(rEnd, rhkEnd, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qIgnoreIndexSettings, qhkIgnoreIndexSettings, _ := qs.GetOK("ignore_index_settings")
+	if err := o.bindIgnoreIndexSettings(qIgnoreIndexSettings, qhkIgnoreIndexSettings, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qIncludeAliases, qhkIncludeAliases, _ := qs.GetOK("include_aliases")
+	if err := o.bindIncludeAliases(qIncludeAliases, qhkIncludeAliases, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qIncludeGlobalState, qhkIncludeGlobalState, _ := qs.GetOK("include_global_state")
+	if err := o.bindIncludeGlobalState(qIncludeGlobalState, qhkIncludeGlobalState, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qIndexSettings, qhkIndexSettings, _ := qs.GetOK("index_settings")
+	if err := o.bindIndexSettings(qIndexSettings, qhkIndexSettings, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	rName, rhkName, _ := route.Params.GetOK("name")
+	if err := o.bindName(rName, rhkName, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	rStart, rhkStart, _ := route.Params.GetOK("start")
+	if err := o.bindStart(rStart, rhkStart, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qTTL, qhkTTL, _ := qs.GetOK("ttl")
+	if err := o.bindTTL(qTTL, qhkTTL, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (o *PostDatasetsNameStartEndParams) bindAlias(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.Alias = &raw
+
+	return nil
+}
+
+func (o *PostDatasetsNameStartEndParams) bindEnd(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	o.End = raw
+
+	return nil
+}
+
+func (o *PostDatasetsNameStartEndParams) bindIgnoreIndexSettings(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.IgnoreIndexSettings = &raw
+
+	return nil
+}
+
+func (o *PostDatasetsNameStartEndParams) bindIncludeAliases(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	value, err := swag.ConvertBool(raw)
+	if err != nil {
+		return errors.InvalidType("include_aliases", "query", "bool", raw)
+	}
+	o.IncludeAliases = &value
+
+	return nil
+}
+
+func (o *PostDatasetsNameStartEndParams) bindIncludeGlobalState(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	value, err := swag.ConvertBool(raw)
+	if err != nil {
+		return errors.InvalidType("include_global_state", "query", "bool", raw)
+	}
+	o.IncludeGlobalState = &value
+
+	return nil
+}
+
+func (o *PostDatasetsNameStartEndParams) bindIndexSettings(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.IndexSettings = &raw
+
+	return nil
+}
+
+func (o *PostDatasetsNameStartEndParams) bindName(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	o.Name = raw
+
+	return nil
+}
+
+func (o *PostDatasetsNameStartEndParams) bindStart(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	o.Start = raw
+
+	return nil
+}
+
+func (o *PostDatasetsNameStartEndParams) bindTTL(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.TTL = &raw
+
+	return nil
+}
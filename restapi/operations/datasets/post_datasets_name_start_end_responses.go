@@ -0,0 +1,344 @@
+package datasets
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/danisla/esio/models"
+)
+
+/*PostDatasetsNameStartEndOK All indices in [start,end] range are availble and ready.
+
+swagger:response postDatasetsNameStartEndOK
+*/
+type PostDatasetsNameStartEndOK struct {
+
+	/*URL of the job created for this request.
+	 */
+	Location string `json:"Location"`
+
+	// In: body
+	Payload *models.IndiceStatus `json:"body,omitempty"`
+}
+
+// NewPostDatasetsNameStartEndOK creates PostDatasetsNameStartEndOK with default headers values
+func NewPostDatasetsNameStartEndOK() *PostDatasetsNameStartEndOK {
+	return &PostDatasetsNameStartEndOK{}
+}
+
+// WithLocation adds the location to the post datasets name start end o k response
+func (o *PostDatasetsNameStartEndOK) WithLocation(location string) *PostDatasetsNameStartEndOK {
+	o.Location = location
+	return o
+}
+
+// SetLocation sets the location to the post datasets name start end o k response
+func (o *PostDatasetsNameStartEndOK) SetLocation(location string) {
+	o.Location = location
+}
+
+// WithPayload adds the payload to the post datasets name start end o k response
+func (o *PostDatasetsNameStartEndOK) WithPayload(payload *models.IndiceStatus) *PostDatasetsNameStartEndOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the post datasets name start end o k response
+func (o *PostDatasetsNameStartEndOK) SetPayload(payload *models.IndiceStatus) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *PostDatasetsNameStartEndOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	// response header Location
+	rw.Header().Set("Location", fmt.Sprintf("%v", o.Location))
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*PostDatasetsNameStartEndAccepted Index restore started
+
+swagger:response postDatasetsNameStartEndAccepted
+*/
+type PostDatasetsNameStartEndAccepted struct {
+
+	/*URL of the job created for this request.
+	 */
+	Location string `json:"Location"`
+
+	// In: body
+	Payload *models.IndiceStatus `json:"body,omitempty"`
+}
+
+// NewPostDatasetsNameStartEndAccepted creates PostDatasetsNameStartEndAccepted with default headers values
+func NewPostDatasetsNameStartEndAccepted() *PostDatasetsNameStartEndAccepted {
+	return &PostDatasetsNameStartEndAccepted{}
+}
+
+// WithLocation adds the location to the post datasets name start end accepted response
+func (o *PostDatasetsNameStartEndAccepted) WithLocation(location string) *PostDatasetsNameStartEndAccepted {
+	o.Location = location
+	return o
+}
+
+// SetLocation sets the location to the post datasets name start end accepted response
+func (o *PostDatasetsNameStartEndAccepted) SetLocation(location string) {
+	o.Location = location
+}
+
+// WithPayload adds the payload to the post datasets name start end accepted response
+func (o *PostDatasetsNameStartEndAccepted) WithPayload(payload *models.IndiceStatus) *PostDatasetsNameStartEndAccepted {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the post datasets name start end accepted response
+func (o *PostDatasetsNameStartEndAccepted) SetPayload(payload *models.IndiceStatus) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *PostDatasetsNameStartEndAccepted) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	// response header Location
+	rw.Header().Set("Location", fmt.Sprintf("%v", o.Location))
+
+	rw.WriteHeader(202)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*PostDatasetsNameStartEndPartialContent Zero or more of the indices in the [start,end] range are available and ready.
+
+swagger:response postDatasetsNameStartEndPartialContent
+*/
+type PostDatasetsNameStartEndPartialContent struct {
+
+	/*URL of the job created for this request.
+	 */
+	Location string `json:"Location"`
+
+	// In: body
+	Payload *models.IndiceStatus `json:"body,omitempty"`
+}
+
+// NewPostDatasetsNameStartEndPartialContent creates PostDatasetsNameStartEndPartialContent with default headers values
+func NewPostDatasetsNameStartEndPartialContent() *PostDatasetsNameStartEndPartialContent {
+	return &PostDatasetsNameStartEndPartialContent{}
+}
+
+// WithLocation adds the location to the post datasets name start end partial content response
+func (o *PostDatasetsNameStartEndPartialContent) WithLocation(location string) *PostDatasetsNameStartEndPartialContent {
+	o.Location = location
+	return o
+}
+
+// SetLocation sets the location to the post datasets name start end partial content response
+func (o *PostDatasetsNameStartEndPartialContent) SetLocation(location string) {
+	o.Location = location
+}
+
+// WithPayload adds the payload to the post datasets name start end partial content response
+func (o *PostDatasetsNameStartEndPartialContent) WithPayload(payload *models.IndiceStatus) *PostDatasetsNameStartEndPartialContent {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the post datasets name start end partial content response
+func (o *PostDatasetsNameStartEndPartialContent) SetPayload(payload *models.IndiceStatus) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *PostDatasetsNameStartEndPartialContent) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	// response header Location
+	rw.Header().Set("Location", fmt.Sprintf("%v", o.Location))
+
+	rw.WriteHeader(206)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*PostDatasetsNameStartEndBadRequest invalid time range provided or dataset not found
+
+swagger:response postDatasetsNameStartEndBadRequest
+*/
+type PostDatasetsNameStartEndBadRequest struct {
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewPostDatasetsNameStartEndBadRequest creates PostDatasetsNameStartEndBadRequest with default headers values
+func NewPostDatasetsNameStartEndBadRequest() *PostDatasetsNameStartEndBadRequest {
+	return &PostDatasetsNameStartEndBadRequest{}
+}
+
+// WithPayload adds the payload to the post datasets name start end bad request response
+func (o *PostDatasetsNameStartEndBadRequest) WithPayload(payload *models.Error) *PostDatasetsNameStartEndBadRequest {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the post datasets name start end bad request response
+func (o *PostDatasetsNameStartEndBadRequest) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *PostDatasetsNameStartEndBadRequest) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(400)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*PostDatasetsNameStartEndConflict Indices in range are already restored with other restore options and have to be deleted first.
+
+swagger:response postDatasetsNameStartEndConflict
+*/
+type PostDatasetsNameStartEndConflict struct {
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewPostDatasetsNameStartEndConflict creates PostDatasetsNameStartEndConflict with default headers values
+func NewPostDatasetsNameStartEndConflict() *PostDatasetsNameStartEndConflict {
+	return &PostDatasetsNameStartEndConflict{}
+}
+
+// WithPayload adds the payload to the post datasets name start end conflict response
+func (o *PostDatasetsNameStartEndConflict) WithPayload(payload *models.Error) *PostDatasetsNameStartEndConflict {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the post datasets name start end conflict response
+func (o *PostDatasetsNameStartEndConflict) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *PostDatasetsNameStartEndConflict) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(409)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*PostDatasetsNameStartEndRequestRangeNotSatisfiable Not all indices in given [start,end] range were found to restore.
+
+swagger:response postDatasetsNameStartEndRequestRangeNotSatisfiable
+*/
+type PostDatasetsNameStartEndRequestRangeNotSatisfiable struct {
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewPostDatasetsNameStartEndRequestRangeNotSatisfiable creates PostDatasetsNameStartEndRequestRangeNotSatisfiable with default headers values
+func NewPostDatasetsNameStartEndRequestRangeNotSatisfiable() *PostDatasetsNameStartEndRequestRangeNotSatisfiable {
+	return &PostDatasetsNameStartEndRequestRangeNotSatisfiable{}
+}
+
+// WithPayload adds the payload to the post datasets name start end request range not satisfiable response
+func (o *PostDatasetsNameStartEndRequestRangeNotSatisfiable) WithPayload(payload *models.Error) *PostDatasetsNameStartEndRequestRangeNotSatisfiable {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the post datasets name start end request range not satisfiable response
+func (o *PostDatasetsNameStartEndRequestRangeNotSatisfiable) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *PostDatasetsNameStartEndRequestRangeNotSatisfiable) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(416)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*PostDatasetsNameStartEndDefault Unexpected error
+
+swagger:response postDatasetsNameStartEndDefault
+*/
+type PostDatasetsNameStartEndDefault struct {
+	_statusCode int
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewPostDatasetsNameStartEndDefault creates PostDatasetsNameStartEndDefault with default headers values
+func NewPostDatasetsNameStartEndDefault(code int) *PostDatasetsNameStartEndDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &PostDatasetsNameStartEndDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the post datasets name start end default response
+func (o *PostDatasetsNameStartEndDefault) WithStatusCode(code int) *PostDatasetsNameStartEndDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the post datasets name start end default response
+func (o *PostDatasetsNameStartEndDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the post datasets name start end default response
+func (o *PostDatasetsNameStartEndDefault) WithPayload(payload *models.Error) *PostDatasetsNameStartEndDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the post datasets name start end default response
+func (o *PostDatasetsNameStartEndDefault) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *PostDatasetsNameStartEndDefault) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
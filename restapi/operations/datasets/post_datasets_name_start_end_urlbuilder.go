@@ -0,0 +1,150 @@
+package datasets
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/go-openapi/swag"
+)
+
+// PostDatasetsNameStartEndURL generates an URL for the post datasets name start end operation
+type PostDatasetsNameStartEndURL struct {
+	End   string
+	Name  string
+	Start string
+
+	Alias               *string
+	IgnoreIndexSettings *string
+	IncludeAliases      *bool
+	IncludeGlobalState  *bool
+	IndexSettings       *string
+	TTL                 *string
+
+	// avoid unkeyed usage
+	_ struct{}
+}
+
+// Build a url path and query string
+func (o *PostDatasetsNameStartEndURL) Build() (*url.URL, error) {
+	var result url.URL
+
+	var _path = "/datasets/{name}/{start}/{end}"
+
+	end := o.End
+	if end != "" {
+		_path = strings.Replace(_path, "{end}", end, -1)
+	} else {
+		return nil, errors.New("End is required on PostDatasetsNameStartEndURL")
+	}
+	name := o.Name
+	if name != "" {
+		_path = strings.Replace(_path, "{name}", name, -1)
+	} else {
+		return nil, errors.New("Name is required on PostDatasetsNameStartEndURL")
+	}
+	start := o.Start
+	if start != "" {
+		_path = strings.Replace(_path, "{start}", start, -1)
+	} else {
+		return nil, errors.New("Start is required on PostDatasetsNameStartEndURL")
+	}
+	result.Path = _path
+
+	qs := make(url.Values)
+
+	var alias string
+	if o.Alias != nil {
+		alias = *o.Alias
+	}
+	if alias != "" {
+		qs.Set("alias", alias)
+	}
+
+	var ignoreIndexSettings string
+	if o.IgnoreIndexSettings != nil {
+		ignoreIndexSettings = *o.IgnoreIndexSettings
+	}
+	if ignoreIndexSettings != "" {
+		qs.Set("ignore_index_settings", ignoreIndexSettings)
+	}
+
+	var includeAliases string
+	if o.IncludeAliases != nil {
+		includeAliases = swag.FormatBool(*o.IncludeAliases)
+	}
+	if includeAliases != "" {
+		qs.Set("include_aliases", includeAliases)
+	}
+
+	var includeGlobalState string
+	if o.IncludeGlobalState != nil {
+		includeGlobalState = swag.FormatBool(*o.IncludeGlobalState)
+	}
+	if includeGlobalState != "" {
+		qs.Set("include_global_state", includeGlobalState)
+	}
+
+	var indexSettings string
+	if o.IndexSettings != nil {
+		indexSettings = *o.IndexSettings
+	}
+	if indexSettings != "" {
+		qs.Set("index_settings", indexSettings)
+	}
+
+	var tTL string
+	if o.TTL != nil {
+		tTL = *o.TTL
+	}
+	if tTL != "" {
+		qs.Set("ttl", tTL)
+	}
+
+	result.RawQuery = qs.Encode()
+
+	return &result, nil
+}
+
+// Must is a helper function to panic when the url builder returns an error
+func (o *PostDatasetsNameStartEndURL) Must(u *url.URL, err error) *url.URL {
+	if err != nil {
+		panic(err)
+	}
+	if u == nil {
+		panic("url can't be nil")
+	}
+	return u
+}
+
+// String returns the string representation of the path with query string
+func (o *PostDatasetsNameStartEndURL) String() string {
+	return o.Must(o.Build()).String()
+}
+
+// BuildFull builds a full url with scheme, host, path and query string
+func (o *PostDatasetsNameStartEndURL) BuildFull(scheme, host string) (*url.URL, error) {
+	if scheme == "" {
+		return nil, errors.New("scheme is required for a full url on PostDatasetsNameStartEndURL")
+	}
+	if host == "" {
+		return nil, errors.New("host is required for a full url on PostDatasetsNameStartEndURL")
+	}
+
+	base, err := o.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	base.Scheme = scheme
+	base.Host = host
+	return base, nil
+}
+
+// StringFull returns the string representation of a complete url
+func (o *PostDatasetsNameStartEndURL) StringFull(scheme, host string) string {
+	return o.Must(o.BuildFull(scheme, host)).String()
+}
@@ -15,8 +15,12 @@ import (
 	strfmt "github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
 
+	"github.com/danisla/esio/restapi/operations/admin"
+	"github.com/danisla/esio/restapi/operations/datasets"
 	"github.com/danisla/esio/restapi/operations/health"
 	"github.com/danisla/esio/restapi/operations/index"
+	"github.com/danisla/esio/restapi/operations/jobs"
+	"github.com/danisla/esio/restapi/operations/settings"
 )
 
 // NewEsioAPI creates a new Esio instance
@@ -46,14 +50,34 @@ type EsioAPI struct {
 	// JSONProducer registers a producer for a "application/json" mime type
 	JSONProducer runtime.Producer
 
+	// DatasetsDeleteDatasetsNameStartEndHandler sets the operation handler for the delete datasets name start end operation
+	DatasetsDeleteDatasetsNameStartEndHandler datasets.DeleteDatasetsNameStartEndHandler
+	// JobsDeleteJobsIDHandler sets the operation handler for the delete jobs ID operation
+	JobsDeleteJobsIDHandler jobs.DeleteJobsIDHandler
 	// IndexDeleteStartEndHandler sets the operation handler for the delete start end operation
 	IndexDeleteStartEndHandler index.DeleteStartEndHandler
+	// DatasetsGetDatasetsHandler sets the operation handler for the get datasets operation
+	DatasetsGetDatasetsHandler datasets.GetDatasetsHandler
+	// DatasetsGetDatasetsNameStartEndHandler sets the operation handler for the get datasets name start end operation
+	DatasetsGetDatasetsNameStartEndHandler datasets.GetDatasetsNameStartEndHandler
 	// HealthGetHealthzHandler sets the operation handler for the get healthz operation
 	HealthGetHealthzHandler health.GetHealthzHandler
+	// JobsGetJobsHandler sets the operation handler for the get jobs operation
+	JobsGetJobsHandler jobs.GetJobsHandler
+	// JobsGetJobsIDHandler sets the operation handler for the get jobs ID operation
+	JobsGetJobsIDHandler jobs.GetJobsIDHandler
+	// SettingsGetSettingsHandler sets the operation handler for the get settings operation
+	SettingsGetSettingsHandler settings.GetSettingsHandler
 	// IndexGetStartEndHandler sets the operation handler for the get start end operation
 	IndexGetStartEndHandler index.GetStartEndHandler
+	// AdminPostAdminSnapshotsRefreshHandler sets the operation handler for the post admin snapshots refresh operation
+	AdminPostAdminSnapshotsRefreshHandler admin.PostAdminSnapshotsRefreshHandler
+	// DatasetsPostDatasetsNameStartEndHandler sets the operation handler for the post datasets name start end operation
+	DatasetsPostDatasetsNameStartEndHandler datasets.PostDatasetsNameStartEndHandler
 	// IndexPostStartEndHandler sets the operation handler for the post start end operation
 	IndexPostStartEndHandler index.PostStartEndHandler
+	// SettingsPutSettingsHandler sets the operation handler for the put settings operation
+	SettingsPutSettingsHandler settings.PutSettingsHandler
 
 	// ServeError is called when an error is received, there is a default handler
 	// but you can set your own with this
@@ -117,22 +141,62 @@ func (o *EsioAPI) Validate() error {
 		unregistered = append(unregistered, "JSONProducer")
 	}
 
+	if o.DatasetsDeleteDatasetsNameStartEndHandler == nil {
+		unregistered = append(unregistered, "datasets.DeleteDatasetsNameStartEndHandler")
+	}
+
+	if o.JobsDeleteJobsIDHandler == nil {
+		unregistered = append(unregistered, "jobs.DeleteJobsIDHandler")
+	}
+
 	if o.IndexDeleteStartEndHandler == nil {
 		unregistered = append(unregistered, "index.DeleteStartEndHandler")
 	}
 
+	if o.DatasetsGetDatasetsHandler == nil {
+		unregistered = append(unregistered, "datasets.GetDatasetsHandler")
+	}
+
+	if o.DatasetsGetDatasetsNameStartEndHandler == nil {
+		unregistered = append(unregistered, "datasets.GetDatasetsNameStartEndHandler")
+	}
+
 	if o.HealthGetHealthzHandler == nil {
 		unregistered = append(unregistered, "health.GetHealthzHandler")
 	}
 
+	if o.JobsGetJobsHandler == nil {
+		unregistered = append(unregistered, "jobs.GetJobsHandler")
+	}
+
+	if o.JobsGetJobsIDHandler == nil {
+		unregistered = append(unregistered, "jobs.GetJobsIDHandler")
+	}
+
+	if o.SettingsGetSettingsHandler == nil {
+		unregistered = append(unregistered, "settings.GetSettingsHandler")
+	}
+
 	if o.IndexGetStartEndHandler == nil {
 		unregistered = append(unregistered, "index.GetStartEndHandler")
 	}
 
+	if o.AdminPostAdminSnapshotsRefreshHandler == nil {
+		unregistered = append(unregistered, "admin.PostAdminSnapshotsRefreshHandler")
+	}
+
+	if o.DatasetsPostDatasetsNameStartEndHandler == nil {
+		unregistered = append(unregistered, "datasets.PostDatasetsNameStartEndHandler")
+	}
+
 	if o.IndexPostStartEndHandler == nil {
 		unregistered = append(unregistered, "index.PostStartEndHandler")
 	}
 
+	if o.SettingsPutSettingsHandler == nil {
+		unregistered = append(unregistered, "settings.PutSettingsHandler")
+	}
+
 	if len(unregistered) > 0 {
 		return fmt.Errorf("missing registration: %s", strings.Join(unregistered, ", "))
 	}
@@ -213,26 +277,76 @@ func (o *EsioAPI) initHandlerCache() {
 		o.handlers = make(map[string]map[string]http.Handler)
 	}
 
+	if o.handlers["DELETE"] == nil {
+		o.handlers[strings.ToUpper("DELETE")] = make(map[string]http.Handler)
+	}
+	o.handlers["DELETE"]["/datasets/{name}/{start}/{end}"] = datasets.NewDeleteDatasetsNameStartEnd(o.context, o.DatasetsDeleteDatasetsNameStartEndHandler)
+
+	if o.handlers["DELETE"] == nil {
+		o.handlers[strings.ToUpper("DELETE")] = make(map[string]http.Handler)
+	}
+	o.handlers["DELETE"]["/jobs/{id}"] = jobs.NewDeleteJobsID(o.context, o.JobsDeleteJobsIDHandler)
+
 	if o.handlers["DELETE"] == nil {
 		o.handlers[strings.ToUpper("DELETE")] = make(map[string]http.Handler)
 	}
 	o.handlers["DELETE"]["/{start}/{end}"] = index.NewDeleteStartEnd(o.context, o.IndexDeleteStartEndHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers[strings.ToUpper("GET")] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/datasets"] = datasets.NewGetDatasets(o.context, o.DatasetsGetDatasetsHandler)
+
+	if o.handlers["GET"] == nil {
+		o.handlers[strings.ToUpper("GET")] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/datasets/{name}/{start}/{end}"] = datasets.NewGetDatasetsNameStartEnd(o.context, o.DatasetsGetDatasetsNameStartEndHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers[strings.ToUpper("GET")] = make(map[string]http.Handler)
 	}
 	o.handlers["GET"]["/healthz"] = health.NewGetHealthz(o.context, o.HealthGetHealthzHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers[strings.ToUpper("GET")] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/jobs"] = jobs.NewGetJobs(o.context, o.JobsGetJobsHandler)
+
+	if o.handlers["GET"] == nil {
+		o.handlers[strings.ToUpper("GET")] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/jobs/{id}"] = jobs.NewGetJobsID(o.context, o.JobsGetJobsIDHandler)
+
+	if o.handlers["GET"] == nil {
+		o.handlers[strings.ToUpper("GET")] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/settings"] = settings.NewGetSettings(o.context, o.SettingsGetSettingsHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers[strings.ToUpper("GET")] = make(map[string]http.Handler)
 	}
 	o.handlers["GET"]["/{start}/{end}"] = index.NewGetStartEnd(o.context, o.IndexGetStartEndHandler)
 
+	if o.handlers["POST"] == nil {
+		o.handlers[strings.ToUpper("POST")] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/admin/snapshots/refresh"] = admin.NewPostAdminSnapshotsRefresh(o.context, o.AdminPostAdminSnapshotsRefreshHandler)
+
+	if o.handlers["POST"] == nil {
+		o.handlers[strings.ToUpper("POST")] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/datasets/{name}/{start}/{end}"] = datasets.NewPostDatasetsNameStartEnd(o.context, o.DatasetsPostDatasetsNameStartEndHandler)
+
 	if o.handlers["POST"] == nil {
 		o.handlers[strings.ToUpper("POST")] = make(map[string]http.Handler)
 	}
 	o.handlers["POST"]["/{start}/{end}"] = index.NewPostStartEnd(o.context, o.IndexPostStartEndHandler)
 
+	if o.handlers["PUT"] == nil {
+		o.handlers[strings.ToUpper("PUT")] = make(map[string]http.Handler)
+	}
+	o.handlers["PUT"]["/settings"] = settings.NewPutSettings(o.context, o.SettingsPutSettingsHandler)
+
 }
 
 // Serve creates a http handler to serve the API over HTTP
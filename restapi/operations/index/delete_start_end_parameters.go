@@ -9,7 +9,7 @@ import (
 	"github.com/go-openapi/errors"
 	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
-	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
 
 	strfmt "github.com/go-openapi/strfmt"
 )
@@ -30,24 +30,36 @@ type DeleteStartEndParams struct {
 	// HTTP Request Object
 	HTTPRequest *http.Request
 
-	/*end time, unix timestamp
+	/*End time, unix timestamp, RFC3339 time, date like 2016-04-07 or relative time like now
 	  Required: true
 	  In: path
 	*/
-	End int64
+	End string
+	/*Optional regex the restored index names are matched with, restored indices are renamed with rename_replacement, must be URL encoded.
+	  In: query
+	*/
+	RenamePattern *string
+	/*Optional replacement of the restored index names matched by rename_pattern, ex restored-$1, must be URL encoded.
+	  In: query
+	*/
+	RenameReplacement *string
 	/*Optional override of the repo pattern, must be URL encoded.
 	  In: query
 	*/
 	RepoPattern *string
-	/*Optional override of the index resolution, must be 'day', 'month', or 'year'
+	/*Optional override of the index resolution, must be 'hour', 'day', 'week', 'month', 'year' or a duration like '6h'
 	  In: query
 	*/
 	Resolution *string
-	/*start time, unix timestamp
+	/*Start time, unix timestamp, RFC3339 time, date like 2016-04-07 or relative time like now-7d
 	  Required: true
 	  In: path
 	*/
-	Start int64
+	Start string
+	/*Optional override of the IANA timezone index names are computed in, ex America/Los_Angeles, must be URL encoded.
+	  In: query
+	*/
+	Tz *string
 }
 
 // BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
@@ -63,6 +75,16 @@ func (o *DeleteStartEndParams) BindRequest(r *http.Request, route *middleware.Ma
 		res = append(res, err)
 	}
 
+	qRenamePattern, qhkRenamePattern, _ := qs.GetOK("rename_pattern")
+	if err := o.bindRenamePattern(qRenamePattern, qhkRenamePattern, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qRenameReplacement, qhkRenameReplacement, _ := qs.GetOK("rename_replacement")
+	if err := o.bindRenameReplacement(qRenameReplacement, qhkRenameReplacement, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	qRepoPattern, qhkRepoPattern, _ := qs.GetOK("repo_pattern")
 	if err := o.bindRepoPattern(qRepoPattern, qhkRepoPattern, route.Formats); err != nil {
 		res = append(res, err)
@@ -78,6 +100,11 @@ func (o *DeleteStartEndParams) BindRequest(r *http.Request, route *middleware.Ma
 		res = append(res, err)
 	}
 
+	qTz, qhkTz, _ := qs.GetOK("tz")
+	if err := o.bindTz(qTz, qhkTz, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -90,11 +117,35 @@ func (o *DeleteStartEndParams) bindEnd(rawData []string, hasKey bool, formats st
 		raw = rawData[len(rawData)-1]
 	}
 
-	value, err := swag.ConvertInt64(raw)
-	if err != nil {
-		return errors.InvalidType("end", "path", "int64", raw)
+	o.End = raw
+
+	return nil
+}
+
+func (o *DeleteStartEndParams) bindRenamePattern(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
 	}
-	o.End = value
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.RenamePattern = &raw
+
+	return nil
+}
+
+func (o *DeleteStartEndParams) bindRenameReplacement(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.RenameReplacement = &raw
 
 	return nil
 }
@@ -124,6 +175,19 @@ func (o *DeleteStartEndParams) bindResolution(rawData []string, hasKey bool, for
 
 	o.Resolution = &raw
 
+	if err := o.validateResolution(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o *DeleteStartEndParams) validateResolution(formats strfmt.Registry) error {
+
+	if err := validate.Pattern("resolution", "query", string(*o.Resolution), `^(hour|day|week|month|year|([0-9]+(m|h))+)$`); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -133,11 +197,21 @@ func (o *DeleteStartEndParams) bindStart(rawData []string, hasKey bool, formats
 		raw = rawData[len(rawData)-1]
 	}
 
-	value, err := swag.ConvertInt64(raw)
-	if err != nil {
-		return errors.InvalidType("start", "path", "int64", raw)
+	o.Start = raw
+
+	return nil
+}
+
+func (o *DeleteStartEndParams) bindTz(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
 	}
-	o.Start = value
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.Tz = &raw
 
 	return nil
 }
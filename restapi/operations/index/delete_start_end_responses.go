@@ -4,6 +4,7 @@ package index
 // Editing this file might prove futile when you re-run the swagger generate command
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/go-openapi/runtime"
@@ -17,6 +18,10 @@ swagger:response deleteStartEndOK
 */
 type DeleteStartEndOK struct {
 
+	/*URL of the job created for this request.
+	 */
+	Location string `json:"Location"`
+
 	// In: body
 	Payload *models.IndiceStatus `json:"body,omitempty"`
 }
@@ -26,6 +31,17 @@ func NewDeleteStartEndOK() *DeleteStartEndOK {
 	return &DeleteStartEndOK{}
 }
 
+// WithLocation adds the location to the delete start end o k response
+func (o *DeleteStartEndOK) WithLocation(location string) *DeleteStartEndOK {
+	o.Location = location
+	return o
+}
+
+// SetLocation sets the location to the delete start end o k response
+func (o *DeleteStartEndOK) SetLocation(location string) {
+	o.Location = location
+}
+
 // WithPayload adds the payload to the delete start end o k response
 func (o *DeleteStartEndOK) WithPayload(payload *models.IndiceStatus) *DeleteStartEndOK {
 	o.Payload = payload
@@ -40,6 +56,9 @@ func (o *DeleteStartEndOK) SetPayload(payload *models.IndiceStatus) {
 // WriteResponse to the client
 func (o *DeleteStartEndOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
 
+	// response header Location
+	rw.Header().Set("Location", fmt.Sprintf("%v", o.Location))
+
 	rw.WriteHeader(200)
 	if o.Payload != nil {
 		if err := producer.Produce(rw, o.Payload); err != nil {
@@ -54,6 +73,10 @@ swagger:response deleteStartEndAccepted
 */
 type DeleteStartEndAccepted struct {
 
+	/*URL of the job created for this request.
+	 */
+	Location string `json:"Location"`
+
 	// In: body
 	Payload *models.IndiceStatus `json:"body,omitempty"`
 }
@@ -63,6 +86,17 @@ func NewDeleteStartEndAccepted() *DeleteStartEndAccepted {
 	return &DeleteStartEndAccepted{}
 }
 
+// WithLocation adds the location to the delete start end accepted response
+func (o *DeleteStartEndAccepted) WithLocation(location string) *DeleteStartEndAccepted {
+	o.Location = location
+	return o
+}
+
+// SetLocation sets the location to the delete start end accepted response
+func (o *DeleteStartEndAccepted) SetLocation(location string) {
+	o.Location = location
+}
+
 // WithPayload adds the payload to the delete start end accepted response
 func (o *DeleteStartEndAccepted) WithPayload(payload *models.IndiceStatus) *DeleteStartEndAccepted {
 	o.Payload = payload
@@ -77,6 +111,9 @@ func (o *DeleteStartEndAccepted) SetPayload(payload *models.IndiceStatus) {
 // WriteResponse to the client
 func (o *DeleteStartEndAccepted) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
 
+	// response header Location
+	rw.Header().Set("Location", fmt.Sprintf("%v", o.Location))
+
 	rw.WriteHeader(202)
 	if o.Payload != nil {
 		if err := producer.Produce(rw, o.Payload); err != nil {
@@ -7,17 +7,18 @@ import (
 	"errors"
 	"net/url"
 	"strings"
-
-	"github.com/go-openapi/swag"
 )
 
 // GetStartEndURL generates an URL for the get start end operation
 type GetStartEndURL struct {
-	End   int64
-	Start int64
+	End   string
+	Start string
 
-	RepoPattern *string
-	Resolution  *string
+	RenamePattern     *string
+	RenameReplacement *string
+	RepoPattern       *string
+	Resolution        *string
+	Tz                *string
 
 	// avoid unkeyed usage
 	_ struct{}
@@ -29,13 +30,13 @@ func (o *GetStartEndURL) Build() (*url.URL, error) {
 
 	var _path = "/{start}/{end}"
 
-	end := swag.FormatInt64(o.End)
+	end := o.End
 	if end != "" {
 		_path = strings.Replace(_path, "{end}", end, -1)
 	} else {
 		return nil, errors.New("End is required on GetStartEndURL")
 	}
-	start := swag.FormatInt64(o.Start)
+	start := o.Start
 	if start != "" {
 		_path = strings.Replace(_path, "{start}", start, -1)
 	} else {
@@ -45,6 +46,22 @@ func (o *GetStartEndURL) Build() (*url.URL, error) {
 
 	qs := make(url.Values)
 
+	var renamePattern string
+	if o.RenamePattern != nil {
+		renamePattern = *o.RenamePattern
+	}
+	if renamePattern != "" {
+		qs.Set("rename_pattern", renamePattern)
+	}
+
+	var renameReplacement string
+	if o.RenameReplacement != nil {
+		renameReplacement = *o.RenameReplacement
+	}
+	if renameReplacement != "" {
+		qs.Set("rename_replacement", renameReplacement)
+	}
+
 	var repoPattern string
 	if o.RepoPattern != nil {
 		repoPattern = *o.RepoPattern
@@ -61,6 +78,14 @@ func (o *GetStartEndURL) Build() (*url.URL, error) {
 		qs.Set("resolution", resolution)
 	}
 
+	var tz string
+	if o.Tz != nil {
+		tz = *o.Tz
+	}
+	if tz != "" {
+		qs.Set("tz", tz)
+	}
+
 	result.RawQuery = qs.Encode()
 
 	return &result, nil
@@ -10,6 +10,7 @@ import (
 	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
 
 	strfmt "github.com/go-openapi/strfmt"
 )
@@ -30,24 +31,60 @@ type PostStartEndParams struct {
 	// HTTP Request Object
 	HTTPRequest *http.Request
 
-	/*end time, unix timestamp
+	/*Optional alias attached to the restored indices once they are green and detached before they are deleted, ex restored-logs. Overrides the server alias.
+	  In: query
+	*/
+	Alias *string
+	/*End time, unix timestamp, RFC3339 time, date like 2016-04-07 or relative time like now
 	  Required: true
 	  In: path
 	*/
-	End int64
+	End string
+	/*Optional comma separated index settings of the snapshot that are not restored, ex index.routing.allocation.*, must be URL encoded.
+	  In: query
+	*/
+	IgnoreIndexSettings *string
+	/*Optional, false to restore the indices without their aliases.
+	  In: query
+	*/
+	IncludeAliases *bool
+	/*Optional, true to also restore the cluster state of the snapshot.
+	  In: query
+	*/
+	IncludeGlobalState *bool
+	/*Optional index settings of the restored indices as a JSON object, ex {"number_of_replicas":0}, must be URL encoded.
+	  In: query
+	*/
+	IndexSettings *string
+	/*Optional regex the restored index names are matched with, restored indices are renamed with rename_replacement, must be URL encoded.
+	  In: query
+	*/
+	RenamePattern *string
+	/*Optional replacement of the restored index names matched by rename_pattern, ex restored-$1, must be URL encoded.
+	  In: query
+	*/
+	RenameReplacement *string
 	/*Optional override of the repo pattern, must be URL encoded.
 	  In: query
 	*/
 	RepoPattern *string
-	/*Optional override of the index resolution, must be 'day', 'month', or 'year'
+	/*Optional override of the index resolution, must be 'hour', 'day', 'week', 'month', 'year' or a duration like '6h'
 	  In: query
 	*/
 	Resolution *string
-	/*start time, unix timestamp
+	/*Start time, unix timestamp, RFC3339 time, date like 2016-04-07 or relative time like now-7d
 	  Required: true
 	  In: path
 	*/
-	Start int64
+	Start string
+	/*Optional time to keep the restored indices before they are deleted, ex 48h or 7d. Overrides the server default TTL.
+	  In: query
+	*/
+	TTL *string
+	/*Optional override of the IANA timezone index names are computed in, ex America/Los_Angeles, must be URL encoded.
+	  In: query
+	*/
+	Tz *string
 }
 
 // BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
@@ -58,11 +95,46 @@ func (o *PostStartEndParams) BindRequest(r *http.Request, route *middleware.Matc
 
 	qs := runtime.Values(r.URL.Query())
 
+	qAlias, qhkAlias, _ := qs.GetOK("alias")
+	if err := o.bindAlias(qAlias, qhkAlias, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	rEnd, rhkEnd, _ := route.Params.GetOK("end")
 	if err := o.bindEnd(rEnd, rhkEnd, route.Formats); err != nil {
 		res = append(res, err)
 	}
 
+	qIgnoreIndexSettings, qhkIgnoreIndexSettings, _ := qs.GetOK("ignore_index_settings")
+	if err := o.bindIgnoreIndexSettings(qIgnoreIndexSettings, qhkIgnoreIndexSettings, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qIncludeAliases, qhkIncludeAliases, _ := qs.GetOK("include_aliases")
+	if err := o.bindIncludeAliases(qIncludeAliases, qhkIncludeAliases, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qIncludeGlobalState, qhkIncludeGlobalState, _ := qs.GetOK("include_global_state")
+	if err := o.bindIncludeGlobalState(qIncludeGlobalState, qhkIncludeGlobalState, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qIndexSettings, qhkIndexSettings, _ := qs.GetOK("index_settings")
+	if err := o.bindIndexSettings(qIndexSettings, qhkIndexSettings, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qRenamePattern, qhkRenamePattern, _ := qs.GetOK("rename_pattern")
+	if err := o.bindRenamePattern(qRenamePattern, qhkRenamePattern, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qRenameReplacement, qhkRenameReplacement, _ := qs.GetOK("rename_replacement")
+	if err := o.bindRenameReplacement(qRenameReplacement, qhkRenameReplacement, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	qRepoPattern, qhkRepoPattern, _ := qs.GetOK("repo_pattern")
 	if err := o.bindRepoPattern(qRepoPattern, qhkRepoPattern, route.Formats); err != nil {
 		res = append(res, err)
@@ -78,23 +150,135 @@ func (o *PostStartEndParams) BindRequest(r *http.Request, route *middleware.Matc
 		res = append(res, err)
 	}
 
+	qTTL, qhkTTL, _ := qs.GetOK("ttl")
+	if err := o.bindTTL(qTTL, qhkTTL, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qTz, qhkTz, _ := qs.GetOK("tz")
+	if err := o.bindTz(qTz, qhkTz, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
 	return nil
 }
 
+func (o *PostStartEndParams) bindAlias(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.Alias = &raw
+
+	return nil
+}
+
 func (o *PostStartEndParams) bindEnd(rawData []string, hasKey bool, formats strfmt.Registry) error {
 	var raw string
 	if len(rawData) > 0 {
 		raw = rawData[len(rawData)-1]
 	}
 
-	value, err := swag.ConvertInt64(raw)
+	o.End = raw
+
+	return nil
+}
+
+func (o *PostStartEndParams) bindIgnoreIndexSettings(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.IgnoreIndexSettings = &raw
+
+	return nil
+}
+
+func (o *PostStartEndParams) bindIncludeAliases(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	value, err := swag.ConvertBool(raw)
 	if err != nil {
-		return errors.InvalidType("end", "path", "int64", raw)
+		return errors.InvalidType("include_aliases", "query", "bool", raw)
 	}
-	o.End = value
+	o.IncludeAliases = &value
+
+	return nil
+}
+
+func (o *PostStartEndParams) bindIncludeGlobalState(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	value, err := swag.ConvertBool(raw)
+	if err != nil {
+		return errors.InvalidType("include_global_state", "query", "bool", raw)
+	}
+	o.IncludeGlobalState = &value
+
+	return nil
+}
+
+func (o *PostStartEndParams) bindIndexSettings(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.IndexSettings = &raw
+
+	return nil
+}
+
+func (o *PostStartEndParams) bindRenamePattern(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.RenamePattern = &raw
+
+	return nil
+}
+
+func (o *PostStartEndParams) bindRenameReplacement(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.RenameReplacement = &raw
 
 	return nil
 }
@@ -124,6 +308,19 @@ func (o *PostStartEndParams) bindResolution(rawData []string, hasKey bool, forma
 
 	o.Resolution = &raw
 
+	if err := o.validateResolution(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o *PostStartEndParams) validateResolution(formats strfmt.Registry) error {
+
+	if err := validate.Pattern("resolution", "query", string(*o.Resolution), `^(hour|day|week|month|year|([0-9]+(m|h))+)$`); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -133,11 +330,35 @@ func (o *PostStartEndParams) bindStart(rawData []string, hasKey bool, formats st
 		raw = rawData[len(rawData)-1]
 	}
 
-	value, err := swag.ConvertInt64(raw)
-	if err != nil {
-		return errors.InvalidType("start", "path", "int64", raw)
+	o.Start = raw
+
+	return nil
+}
+
+func (o *PostStartEndParams) bindTTL(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.TTL = &raw
+
+	return nil
+}
+
+func (o *PostStartEndParams) bindTz(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
 	}
-	o.Start = value
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.Tz = &raw
 
 	return nil
 }
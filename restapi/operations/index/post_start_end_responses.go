@@ -4,6 +4,7 @@ package index
 // Editing this file might prove futile when you re-run the swagger generate command
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/go-openapi/runtime"
@@ -17,6 +18,10 @@ swagger:response postStartEndOK
 */
 type PostStartEndOK struct {
 
+	/*URL of the job created for this request.
+	 */
+	Location string `json:"Location"`
+
 	// In: body
 	Payload *models.IndiceStatus `json:"body,omitempty"`
 }
@@ -26,6 +31,17 @@ func NewPostStartEndOK() *PostStartEndOK {
 	return &PostStartEndOK{}
 }
 
+// WithLocation adds the location to the post start end o k response
+func (o *PostStartEndOK) WithLocation(location string) *PostStartEndOK {
+	o.Location = location
+	return o
+}
+
+// SetLocation sets the location to the post start end o k response
+func (o *PostStartEndOK) SetLocation(location string) {
+	o.Location = location
+}
+
 // WithPayload adds the payload to the post start end o k response
 func (o *PostStartEndOK) WithPayload(payload *models.IndiceStatus) *PostStartEndOK {
 	o.Payload = payload
@@ -40,6 +56,9 @@ func (o *PostStartEndOK) SetPayload(payload *models.IndiceStatus) {
 // WriteResponse to the client
 func (o *PostStartEndOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
 
+	// response header Location
+	rw.Header().Set("Location", fmt.Sprintf("%v", o.Location))
+
 	rw.WriteHeader(200)
 	if o.Payload != nil {
 		if err := producer.Produce(rw, o.Payload); err != nil {
@@ -54,6 +73,10 @@ swagger:response postStartEndAccepted
 */
 type PostStartEndAccepted struct {
 
+	/*URL of the job created for this request.
+	 */
+	Location string `json:"Location"`
+
 	// In: body
 	Payload *models.IndiceStatus `json:"body,omitempty"`
 }
@@ -63,6 +86,17 @@ func NewPostStartEndAccepted() *PostStartEndAccepted {
 	return &PostStartEndAccepted{}
 }
 
+// WithLocation adds the location to the post start end accepted response
+func (o *PostStartEndAccepted) WithLocation(location string) *PostStartEndAccepted {
+	o.Location = location
+	return o
+}
+
+// SetLocation sets the location to the post start end accepted response
+func (o *PostStartEndAccepted) SetLocation(location string) {
+	o.Location = location
+}
+
 // WithPayload adds the payload to the post start end accepted response
 func (o *PostStartEndAccepted) WithPayload(payload *models.IndiceStatus) *PostStartEndAccepted {
 	o.Payload = payload
@@ -77,6 +111,9 @@ func (o *PostStartEndAccepted) SetPayload(payload *models.IndiceStatus) {
 // WriteResponse to the client
 func (o *PostStartEndAccepted) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
 
+	// response header Location
+	rw.Header().Set("Location", fmt.Sprintf("%v", o.Location))
+
 	rw.WriteHeader(202)
 	if o.Payload != nil {
 		if err := producer.Produce(rw, o.Payload); err != nil {
@@ -91,6 +128,10 @@ swagger:response postStartEndPartialContent
 */
 type PostStartEndPartialContent struct {
 
+	/*URL of the job created for this request.
+	 */
+	Location string `json:"Location"`
+
 	// In: body
 	Payload *models.IndiceStatus `json:"body,omitempty"`
 }
@@ -100,6 +141,17 @@ func NewPostStartEndPartialContent() *PostStartEndPartialContent {
 	return &PostStartEndPartialContent{}
 }
 
+// WithLocation adds the location to the post start end partial content response
+func (o *PostStartEndPartialContent) WithLocation(location string) *PostStartEndPartialContent {
+	o.Location = location
+	return o
+}
+
+// SetLocation sets the location to the post start end partial content response
+func (o *PostStartEndPartialContent) SetLocation(location string) {
+	o.Location = location
+}
+
 // WithPayload adds the payload to the post start end partial content response
 func (o *PostStartEndPartialContent) WithPayload(payload *models.IndiceStatus) *PostStartEndPartialContent {
 	o.Payload = payload
@@ -114,6 +166,9 @@ func (o *PostStartEndPartialContent) SetPayload(payload *models.IndiceStatus) {
 // WriteResponse to the client
 func (o *PostStartEndPartialContent) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
 
+	// response header Location
+	rw.Header().Set("Location", fmt.Sprintf("%v", o.Location))
+
 	rw.WriteHeader(206)
 	if o.Payload != nil {
 		if err := producer.Produce(rw, o.Payload); err != nil {
@@ -159,6 +214,43 @@ func (o *PostStartEndBadRequest) WriteResponse(rw http.ResponseWriter, producer
 	}
 }
 
+/*PostStartEndConflict Indices in range are already restored with other restore options and have to be deleted first.
+
+swagger:response postStartEndConflict
+*/
+type PostStartEndConflict struct {
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewPostStartEndConflict creates PostStartEndConflict with default headers values
+func NewPostStartEndConflict() *PostStartEndConflict {
+	return &PostStartEndConflict{}
+}
+
+// WithPayload adds the payload to the post start end conflict response
+func (o *PostStartEndConflict) WithPayload(payload *models.Error) *PostStartEndConflict {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the post start end conflict response
+func (o *PostStartEndConflict) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *PostStartEndConflict) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(409)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
 /*PostStartEndRequestRangeNotSatisfiable Not all indices in given [start,end] range were found to restore.
 
 swagger:response postStartEndRequestRangeNotSatisfiable
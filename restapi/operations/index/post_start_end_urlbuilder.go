@@ -13,11 +13,20 @@ import (
 
 // PostStartEndURL generates an URL for the post start end operation
 type PostStartEndURL struct {
-	End   int64
-	Start int64
-
-	RepoPattern *string
-	Resolution  *string
+	End   string
+	Start string
+
+	Alias               *string
+	IgnoreIndexSettings *string
+	IncludeAliases      *bool
+	IncludeGlobalState  *bool
+	IndexSettings       *string
+	RenamePattern       *string
+	RenameReplacement   *string
+	RepoPattern         *string
+	Resolution          *string
+	TTL                 *string
+	Tz                  *string
 
 	// avoid unkeyed usage
 	_ struct{}
@@ -29,13 +38,13 @@ func (o *PostStartEndURL) Build() (*url.URL, error) {
 
 	var _path = "/{start}/{end}"
 
-	end := swag.FormatInt64(o.End)
+	end := o.End
 	if end != "" {
 		_path = strings.Replace(_path, "{end}", end, -1)
 	} else {
 		return nil, errors.New("End is required on PostStartEndURL")
 	}
-	start := swag.FormatInt64(o.Start)
+	start := o.Start
 	if start != "" {
 		_path = strings.Replace(_path, "{start}", start, -1)
 	} else {
@@ -45,6 +54,62 @@ func (o *PostStartEndURL) Build() (*url.URL, error) {
 
 	qs := make(url.Values)
 
+	var alias string
+	if o.Alias != nil {
+		alias = *o.Alias
+	}
+	if alias != "" {
+		qs.Set("alias", alias)
+	}
+
+	var ignoreIndexSettings string
+	if o.IgnoreIndexSettings != nil {
+		ignoreIndexSettings = *o.IgnoreIndexSettings
+	}
+	if ignoreIndexSettings != "" {
+		qs.Set("ignore_index_settings", ignoreIndexSettings)
+	}
+
+	var includeAliases string
+	if o.IncludeAliases != nil {
+		includeAliases = swag.FormatBool(*o.IncludeAliases)
+	}
+	if includeAliases != "" {
+		qs.Set("include_aliases", includeAliases)
+	}
+
+	var includeGlobalState string
+	if o.IncludeGlobalState != nil {
+		includeGlobalState = swag.FormatBool(*o.IncludeGlobalState)
+	}
+	if includeGlobalState != "" {
+		qs.Set("include_global_state", includeGlobalState)
+	}
+
+	var indexSettings string
+	if o.IndexSettings != nil {
+		indexSettings = *o.IndexSettings
+	}
+	if indexSettings != "" {
+		qs.Set("index_settings", indexSettings)
+	}
+
+	var renamePattern string
+	if o.RenamePattern != nil {
+		renamePattern = *o.RenamePattern
+	}
+	if renamePattern != "" {
+		qs.Set("rename_pattern", renamePattern)
+	}
+
+	var renameReplacement string
+	if o.RenameReplacement != nil {
+		renameReplacement = *o.RenameReplacement
+	}
+	if renameReplacement != "" {
+		qs.Set("rename_replacement", renameReplacement)
+	}
+
 	var repoPattern string
 	if o.RepoPattern != nil {
 		repoPattern = *o.RepoPattern
@@ -61,6 +126,22 @@ func (o *PostStartEndURL) Build() (*url.URL, error) {
 		qs.Set("resolution", resolution)
 	}
 
+	var tTL string
+	if o.TTL != nil {
+		tTL = *o.TTL
+	}
+	if tTL != "" {
+		qs.Set("ttl", tTL)
+	}
+
+	var tz string
+	if o.Tz != nil {
+		tz = *o.Tz
+	}
+	if tz != "" {
+		qs.Set("tz", tz)
+	}
+
 	result.RawQuery = qs.Encode()
 
 	return &result, nil
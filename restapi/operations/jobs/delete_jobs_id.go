@@ -0,0 +1,53 @@
+package jobs
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	middleware "github.com/go-openapi/runtime/middleware"
+)
+
+// DeleteJobsIDHandlerFunc turns a function with the right signature into a delete jobs ID handler
+type DeleteJobsIDHandlerFunc func(DeleteJobsIDParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn DeleteJobsIDHandlerFunc) Handle(params DeleteJobsIDParams) middleware.Responder {
+	return fn(params)
+}
+
+// DeleteJobsIDHandler interface for that can handle valid delete jobs ID params
+type DeleteJobsIDHandler interface {
+	Handle(DeleteJobsIDParams) middleware.Responder
+}
+
+// NewDeleteJobsID creates a new http.Handler for the delete jobs ID operation
+func NewDeleteJobsID(ctx *middleware.Context, handler DeleteJobsIDHandler) *DeleteJobsID {
+	return &DeleteJobsID{Context: ctx, Handler: handler}
+}
+
+/*DeleteJobsID swagger:route DELETE /jobs/{id} jobs deleteJobsId
+
+DeleteJobsID delete jobs ID API
+
+*/
+type DeleteJobsID struct {
+	Context *middleware.Context
+	Handler DeleteJobsIDHandler
+}
+
+func (o *DeleteJobsID) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, _ := o.Context.RouteInfo(r)
+	var Params = NewDeleteJobsIDParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
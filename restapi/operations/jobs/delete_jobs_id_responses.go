@@ -0,0 +1,178 @@
+package jobs
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/danisla/esio/models"
+)
+
+/*DeleteJobsIDOK Job cancelled, indices of the job still waiting in the queue were removed.
+
+swagger:response deleteJobsIDOK
+*/
+type DeleteJobsIDOK struct {
+
+	// In: body
+	Payload *models.Job `json:"body,omitempty"`
+}
+
+// NewDeleteJobsIDOK creates DeleteJobsIDOK with default headers values
+func NewDeleteJobsIDOK() *DeleteJobsIDOK {
+	return &DeleteJobsIDOK{}
+}
+
+// WithPayload adds the payload to the delete jobs ID o k response
+func (o *DeleteJobsIDOK) WithPayload(payload *models.Job) *DeleteJobsIDOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the delete jobs ID o k response
+func (o *DeleteJobsIDOK) SetPayload(payload *models.Job) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *DeleteJobsIDOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*DeleteJobsIDNotFound Job not found.
+
+swagger:response deleteJobsIDNotFound
+*/
+type DeleteJobsIDNotFound struct {
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewDeleteJobsIDNotFound creates DeleteJobsIDNotFound with default headers values
+func NewDeleteJobsIDNotFound() *DeleteJobsIDNotFound {
+	return &DeleteJobsIDNotFound{}
+}
+
+// WithPayload adds the payload to the delete jobs ID not found response
+func (o *DeleteJobsIDNotFound) WithPayload(payload *models.Error) *DeleteJobsIDNotFound {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the delete jobs ID not found response
+func (o *DeleteJobsIDNotFound) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *DeleteJobsIDNotFound) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(404)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*DeleteJobsIDConflict Job already finished and can no longer be cancelled.
+
+swagger:response deleteJobsIDConflict
+*/
+type DeleteJobsIDConflict struct {
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewDeleteJobsIDConflict creates DeleteJobsIDConflict with default headers values
+func NewDeleteJobsIDConflict() *DeleteJobsIDConflict {
+	return &DeleteJobsIDConflict{}
+}
+
+// WithPayload adds the payload to the delete jobs ID conflict response
+func (o *DeleteJobsIDConflict) WithPayload(payload *models.Error) *DeleteJobsIDConflict {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the delete jobs ID conflict response
+func (o *DeleteJobsIDConflict) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *DeleteJobsIDConflict) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(409)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*DeleteJobsIDDefault Unexpected error
+
+swagger:response deleteJobsIDDefault
+*/
+type DeleteJobsIDDefault struct {
+	_statusCode int
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewDeleteJobsIDDefault creates DeleteJobsIDDefault with default headers values
+func NewDeleteJobsIDDefault(code int) *DeleteJobsIDDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &DeleteJobsIDDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the delete jobs ID default response
+func (o *DeleteJobsIDDefault) WithStatusCode(code int) *DeleteJobsIDDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the delete jobs ID default response
+func (o *DeleteJobsIDDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the delete jobs ID default response
+func (o *DeleteJobsIDDefault) WithPayload(payload *models.Error) *DeleteJobsIDDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the delete jobs ID default response
+func (o *DeleteJobsIDDefault) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *DeleteJobsIDDefault) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
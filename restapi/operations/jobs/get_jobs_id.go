@@ -0,0 +1,53 @@
+package jobs
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	middleware "github.com/go-openapi/runtime/middleware"
+)
+
+// GetJobsIDHandlerFunc turns a function with the right signature into a get jobs ID handler
+type GetJobsIDHandlerFunc func(GetJobsIDParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn GetJobsIDHandlerFunc) Handle(params GetJobsIDParams) middleware.Responder {
+	return fn(params)
+}
+
+// GetJobsIDHandler interface for that can handle valid get jobs ID params
+type GetJobsIDHandler interface {
+	Handle(GetJobsIDParams) middleware.Responder
+}
+
+// NewGetJobsID creates a new http.Handler for the get jobs ID operation
+func NewGetJobsID(ctx *middleware.Context, handler GetJobsIDHandler) *GetJobsID {
+	return &GetJobsID{Context: ctx, Handler: handler}
+}
+
+/*GetJobsID swagger:route GET /jobs/{id} jobs getJobsId
+
+GetJobsID get jobs ID API
+
+*/
+type GetJobsID struct {
+	Context *middleware.Context
+	Handler GetJobsIDHandler
+}
+
+func (o *GetJobsID) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, _ := o.Context.RouteInfo(r)
+	var Params = NewGetJobsIDParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
@@ -0,0 +1,141 @@
+package jobs
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/danisla/esio/models"
+)
+
+/*GetJobsIDOK The job with the given ID.
+
+swagger:response getJobsIDOK
+*/
+type GetJobsIDOK struct {
+
+	// In: body
+	Payload *models.Job `json:"body,omitempty"`
+}
+
+// NewGetJobsIDOK creates GetJobsIDOK with default headers values
+func NewGetJobsIDOK() *GetJobsIDOK {
+	return &GetJobsIDOK{}
+}
+
+// WithPayload adds the payload to the get jobs ID o k response
+func (o *GetJobsIDOK) WithPayload(payload *models.Job) *GetJobsIDOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get jobs ID o k response
+func (o *GetJobsIDOK) SetPayload(payload *models.Job) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetJobsIDOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*GetJobsIDNotFound Job not found.
+
+swagger:response getJobsIDNotFound
+*/
+type GetJobsIDNotFound struct {
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewGetJobsIDNotFound creates GetJobsIDNotFound with default headers values
+func NewGetJobsIDNotFound() *GetJobsIDNotFound {
+	return &GetJobsIDNotFound{}
+}
+
+// WithPayload adds the payload to the get jobs ID not found response
+func (o *GetJobsIDNotFound) WithPayload(payload *models.Error) *GetJobsIDNotFound {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get jobs ID not found response
+func (o *GetJobsIDNotFound) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetJobsIDNotFound) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(404)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*GetJobsIDDefault Unexpected error
+
+swagger:response getJobsIDDefault
+*/
+type GetJobsIDDefault struct {
+	_statusCode int
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewGetJobsIDDefault creates GetJobsIDDefault with default headers values
+func NewGetJobsIDDefault(code int) *GetJobsIDDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &GetJobsIDDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the get jobs ID default response
+func (o *GetJobsIDDefault) WithStatusCode(code int) *GetJobsIDDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the get jobs ID default response
+func (o *GetJobsIDDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the get jobs ID default response
+func (o *GetJobsIDDefault) WithPayload(payload *models.Error) *GetJobsIDDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get jobs ID default response
+func (o *GetJobsIDDefault) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetJobsIDDefault) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
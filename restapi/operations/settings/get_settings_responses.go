@@ -0,0 +1,104 @@
+package settings
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/danisla/esio/models"
+)
+
+/*GetSettingsOK Current runtime settings.
+
+swagger:response getSettingsOK
+*/
+type GetSettingsOK struct {
+
+	// In: body
+	Payload *models.Settings `json:"body,omitempty"`
+}
+
+// NewGetSettingsOK creates GetSettingsOK with default headers values
+func NewGetSettingsOK() *GetSettingsOK {
+	return &GetSettingsOK{}
+}
+
+// WithPayload adds the payload to the get settings o k response
+func (o *GetSettingsOK) WithPayload(payload *models.Settings) *GetSettingsOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get settings o k response
+func (o *GetSettingsOK) SetPayload(payload *models.Settings) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetSettingsOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*GetSettingsDefault Unexpected error
+
+swagger:response getSettingsDefault
+*/
+type GetSettingsDefault struct {
+	_statusCode int
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewGetSettingsDefault creates GetSettingsDefault with default headers values
+func NewGetSettingsDefault(code int) *GetSettingsDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &GetSettingsDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the get settings default response
+func (o *GetSettingsDefault) WithStatusCode(code int) *GetSettingsDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the get settings default response
+func (o *GetSettingsDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the get settings default response
+func (o *GetSettingsDefault) WithPayload(payload *models.Error) *GetSettingsDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get settings default response
+func (o *GetSettingsDefault) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetSettingsDefault) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package settings
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	middleware "github.com/go-openapi/runtime/middleware"
+)
+
+// PutSettingsHandlerFunc turns a function with the right signature into a put settings handler
+type PutSettingsHandlerFunc func(PutSettingsParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn PutSettingsHandlerFunc) Handle(params PutSettingsParams) middleware.Responder {
+	return fn(params)
+}
+
+// PutSettingsHandler interface for that can handle valid put settings params
+type PutSettingsHandler interface {
+	Handle(PutSettingsParams) middleware.Responder
+}
+
+// NewPutSettings creates a new http.Handler for the put settings operation
+func NewPutSettings(ctx *middleware.Context, handler PutSettingsHandler) *PutSettings {
+	return &PutSettings{Context: ctx, Handler: handler}
+}
+
+/*PutSettings swagger:route PUT /settings settings putSettings
+
+PutSettings put settings API
+
+*/
+type PutSettings struct {
+	Context *middleware.Context
+	Handler PutSettingsHandler
+}
+
+func (o *PutSettings) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, _ := o.Context.RouteInfo(r)
+	var Params = NewPutSettingsParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
@@ -0,0 +1,68 @@
+package settings
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware"
+
+	"github.com/danisla/esio/models"
+)
+
+// NewPutSettingsParams creates a new PutSettingsParams object
+// with the default values initialized.
+func NewPutSettingsParams() PutSettingsParams {
+	var ()
+	return PutSettingsParams{}
+}
+
+// PutSettingsParams contains all the bound params for the put settings operation
+// typically these are obtained from a http.Request
+//
+// swagger:parameters PutSettings
+type PutSettingsParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request
+
+	/*Runtime settings to apply.
+	  Required: true
+	  In: body
+	*/
+	Settings *models.Settings
+}
+
+// BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
+// for simple values it will use straight method calls
+func (o *PutSettingsParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
+	var res []error
+	o.HTTPRequest = r
+
+	if runtime.HasBody(r) {
+		defer r.Body.Close()
+		var body models.Settings
+		if err := route.Consumer.Consume(r.Body, &body); err != nil {
+			res = append(res, errors.NewParseError("settings", "body", "", err))
+		} else {
+			if err := body.Validate(route.Formats); err != nil {
+				res = append(res, err)
+			}
+
+			if len(res) == 0 {
+				o.Settings = &body
+			}
+		}
+
+	} else {
+		res = append(res, errors.Required("settings", "body"))
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package settings
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+
+	"github.com/danisla/esio/models"
+)
+
+/*PutSettingsOK Settings were applied.
+
+swagger:response putSettingsOK
+*/
+type PutSettingsOK struct {
+
+	// In: body
+	Payload *models.Settings `json:"body,omitempty"`
+}
+
+// NewPutSettingsOK creates PutSettingsOK with default headers values
+func NewPutSettingsOK() *PutSettingsOK {
+	return &PutSettingsOK{}
+}
+
+// WithPayload adds the payload to the put settings o k response
+func (o *PutSettingsOK) WithPayload(payload *models.Settings) *PutSettingsOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the put settings o k response
+func (o *PutSettingsOK) SetPayload(payload *models.Settings) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *PutSettingsOK) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*PutSettingsDefault Unexpected error
+
+swagger:response putSettingsDefault
+*/
+type PutSettingsDefault struct {
+	_statusCode int
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewPutSettingsDefault creates PutSettingsDefault with default headers values
+func NewPutSettingsDefault(code int) *PutSettingsDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &PutSettingsDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the put settings default response
+func (o *PutSettingsDefault) WithStatusCode(code int) *PutSettingsDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the put settings default response
+func (o *PutSettingsDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the put settings default response
+func (o *PutSettingsDefault) WithPayload(payload *models.Error) *PutSettingsDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the put settings default response
+func (o *PutSettingsDefault) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *PutSettingsDefault) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package restapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// QueueJournal mirrors the contents of a Queue to a JSON file so queued work survives a restart.
+// Values popped by a worker are recorded as in flight until the worker calls Done.
+type QueueJournal struct {
+	path     string
+	inflight []string
+	mu       sync.Mutex
+}
+
+type queueJournalFile struct {
+	Inflight []string `json:"inflight"`
+	Queued   []string `json:"queued"`
+}
+
+// replayQueue attaches a journal stored at path to the queue and re-queues its contents.
+// Values that were in flight when the server stopped are only re-queued if retry returns true.
+func replayQueue(q *Queue, path string, retry func(string) bool) error {
+	var state queueJournalFile
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Error reading queue journal '%s': %s", path, err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &state); err != nil {
+			return fmt.Errorf("Error decoding queue journal '%s': %s", path, err)
+		}
+	}
+
+	// In flight values go first, they were at the head of the queue when the server stopped.
+	for _, value := range state.Inflight {
+		if retry(value) {
+			log.Println(fmt.Sprintf("Re-queueing interrupted item: %s", value))
+			q.Push(&Node{value})
+		} else {
+			log.Println(fmt.Sprintf("Interrupted item no longer needs work: %s", value))
+		}
+	}
+
+	for _, value := range state.Queued {
+		q.Push(&Node{value})
+	}
+
+	if q.Len() > 0 {
+		log.Println(fmt.Sprintf("Replayed %d queued items from journal: %s", q.Len(), path))
+	}
+
+	q.attach(&QueueJournal{path: path, inflight: make([]string, 0)})
+
+	return nil
+}
+
+func (j *QueueJournal) begin(value string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.inflight = append(j.inflight, value)
+}
+
+func (j *QueueJournal) finish(value string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for i, v := range j.inflight {
+		if v == value {
+			j.inflight = append(j.inflight[:i], j.inflight[i+1:]...)
+			return
+		}
+	}
+}
+
+// save atomically replaces the journal file with the given queued values and the current in flight values.
+func (j *QueueJournal) save(queued []string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	data, err := json.Marshal(queueJournalFile{Inflight: j.inflight, Queued: queued})
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(j.path, data)
+}
+
+// writeFileAtomic replaces the file at path with data by writing to a temp file and renaming it.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,208 @@
+package restapi
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/danisla/esio/models"
+	errors "github.com/go-openapi/errors"
+)
+
+// How often the recovery of restoring indices is polled.
+const recoveryPollInterval = 5 * time.Second
+
+// Time without any recovered bytes after which a recovery is reported as stalled.
+const recoveryStallTimeout = 10 * time.Minute
+
+// Recovery is the progress of the primary shards of one restoring index.
+type Recovery struct {
+	Total      int64
+	Recovered  int64
+	Started    time.Time
+	ProgressAt time.Time
+	Stalled    bool
+}
+
+// RecoveryTracker holds the recovery progress of every index being restored, keyed by repo/snap/index path.
+type RecoveryTracker struct {
+	recoveries map[string]*Recovery
+	mu         sync.Mutex
+}
+
+var recoveryTracker = NewRecoveryTracker()
+
+func NewRecoveryTracker() *RecoveryTracker {
+	return &RecoveryTracker{recoveries: make(map[string]*Recovery)}
+}
+
+// Start begins tracking the recovery of each index.
+func (t *RecoveryTracker) Start(indices []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for _, indice := range indices {
+		t.recoveries[indice] = &Recovery{Started: now, ProgressAt: now}
+	}
+}
+
+// Update sets the bytes recovered of an index and returns true the first time the index is found stalled.
+func (t *RecoveryTracker) Update(indice string, total int64, recovered int64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r, ok := t.recoveries[indice]
+	if !ok {
+		return false
+	}
+
+	now := time.Now()
+	r.Total = total
+	if recovered > r.Recovered {
+		r.Recovered = recovered
+		r.ProgressAt = now
+		r.Stalled = false
+		return false
+	}
+
+	if !r.Stalled && now.Sub(r.ProgressAt) > recoveryStallTimeout {
+		r.Stalled = true
+		return true
+	}
+	return false
+}
+
+// Progress returns the recovery progress of an index, false if the index is not being recovered.
+// The ETA is estimated from the average rate since the restore started.
+func (t *RecoveryTracker) Progress(indice string) (models.RestoreProgress, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r, ok := t.recoveries[indice]
+	if !ok {
+		return models.RestoreProgress{}, false
+	}
+
+	progress := models.RestoreProgress{BytesRecovered: r.Recovered, BytesTotal: r.Total, Stalled: r.Stalled}
+	if r.Total > 0 {
+		progress.Percent = float64(r.Recovered) * 100 / float64(r.Total)
+	}
+	elapsed := time.Since(r.Started).Seconds()
+	if r.Recovered > 0 && elapsed > 0 {
+		rate := float64(r.Recovered) / elapsed
+		progress.EtaSeconds = int64(float64(r.Total-r.Recovered) / rate)
+	}
+	return progress, true
+}
+
+// Idle returns the time since the recovery of an index last made progress, 0 if it is not being recovered.
+func (t *RecoveryTracker) Idle(indice string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r, ok := t.recoveries[indice]
+	if !ok {
+		return 0
+	}
+	return time.Since(r.ProgressAt)
+}
+
+// Stop stops tracking the recovery of each index.
+func (t *RecoveryTracker) Stop(indices []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, indice := range indices {
+		delete(t.recoveries, indice)
+	}
+}
+
+// Polls the recovery of the given repo/snap/index paths, under the names they were restored as, until the
+// primary shards of every index are recovered, and returns the error of each index that failed to recover,
+// keyed by repo/snap/index path. Indices that are not recovered within the restore timeout, make no progress
+// for the recovery stall timeout or are gone from the recovery status fail.
+func waitForRecovery(values []string) map[string]error {
+	results := make(map[string]error)
+
+	recoveryTracker.Start(values)
+	defer recoveryTracker.Stop(values)
+
+	pending := make(map[string]string)
+	for _, value := range values {
+		pending[restoredName(value)] = value
+	}
+
+	deadline := time.Now().Add(currentConfig().restoreTimeout)
+	stallTimeout := currentConfig().stallTimeout
+	for {
+		indices := make([]string, 0, len(pending))
+		for index := range pending {
+			indices = append(indices, index)
+		}
+
+		recoveries, err := backend.RecoveryStatus(context.Background(), indices)
+		if err != nil {
+			log.Println(fmt.Sprintf("WARN: could not get recovery status of indices: %v, error: %s", indices, err))
+		} else {
+			// Restored indices are listed from the time their restore is accepted, an index that is not was
+			// deleted or its restore was dropped by the cluster.
+			for index, value := range pending {
+				if _, ok := recoveries[index]; !ok {
+					results[value] = errors.New(500, fmt.Sprintf("Index: %s is missing from the recovery status, it was deleted or its restore failed", value))
+					delete(pending, index)
+				}
+			}
+		}
+
+		for index, shards := range recoveries {
+			value, ok := pending[index]
+			if !ok {
+				continue
+			}
+
+			var total, recovered int64
+			done := true
+			primaries := 0
+			for _, shard := range shards {
+				if !shard.Primary {
+					continue
+				}
+				primaries++
+				total += shard.Index.Size.TotalInBytes
+				recovered += shard.Index.Size.RecoveredInBytes
+				if shard.Stage != "DONE" {
+					done = false
+				}
+			}
+			if primaries == 0 {
+				continue
+			}
+
+			if recoveryTracker.Update(value, total, recovered) {
+				log.Println(fmt.Sprintf("WARN: recovery of index: %s stalled at %d/%d bytes", value, recovered, total))
+			}
+			if done {
+				delete(pending, index)
+			} else if idle := recoveryTracker.Idle(value); stallTimeout > 0 && idle > stallTimeout {
+				results[value] = errors.New(504, fmt.Sprintf("Recovery of index: %s stalled at %d/%d bytes for %s", value, recovered, total, idle))
+				delete(pending, index)
+			}
+		}
+
+		if len(pending) == 0 {
+			return results
+		}
+
+		if time.Now().After(deadline) {
+			for _, value := range pending {
+				results[value] = errors.New(504, fmt.Sprintf("Recovery of index: %s not finished after %s", value, currentConfig().restoreTimeout))
+			}
+			return results
+		}
+
+		time.Sleep(recoveryPollInterval)
+	}
+}
@@ -0,0 +1,33 @@
+package restapi
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWaitForRecovery(t *testing.T) {
+	f := newFakeBackend()
+	defer useFakeBackend(f, &Config{stallTimeout: time.Nanosecond})()
+
+	// logs-a recovers, logs-b was deleted after its restore was accepted and logs-c makes no progress.
+	f.addIndex("logs-a", "green", 10)
+	f.addIndex("logs-c", "red", 0)
+	f.stalled["logs-c"] = true
+	values := []string{"logs/nightly-1/logs-a", "logs/nightly-1/logs-b", "logs/nightly-1/logs-c"}
+
+	results := waitForRecovery(values)
+
+	if err := results["logs/nightly-1/logs-a"]; err != nil {
+		t.Errorf("recovered index failed: %s", err)
+	}
+	if err := results["logs/nightly-1/logs-b"]; err == nil || !strings.Contains(err.Error(), "missing from the recovery status") {
+		t.Errorf("index missing from the recovery status error = %v, want missing", err)
+	}
+	if err := results["logs/nightly-1/logs-c"]; err == nil || !strings.Contains(err.Error(), "stalled at 0/10 bytes") {
+		t.Errorf("stalled index error = %v, want stalled", err)
+	}
+	if _, ok := recoveryTracker.Progress("logs/nightly-1/logs-c"); ok {
+		t.Errorf("recovery of a failed index is still tracked")
+	}
+}
@@ -0,0 +1,436 @@
+package restapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	errors "github.com/go-openapi/errors"
+)
+
+// RestoreOptions are the settings of a restore request other than its indices.
+type RestoreOptions struct {
+	// Restore the indices of a PARTIAL snapshot, shards that are not in the snapshot are left out.
+	// Set for each restore from the state of the snapshot.
+	Partial bool `json:"-"`
+	// Restore the indices under other names.
+	Rename Rename `json:"rename,omitempty"`
+	// Index settings of the restored indices, keyed by full setting name like index.number_of_replicas.
+	IndexSettings map[string]string `json:"index_settings,omitempty"`
+	// Index settings of the snapshot that are not restored.
+	IgnoreIndexSettings []string `json:"ignore_index_settings,omitempty"`
+	// Restore the aliases of the indices, the cluster default is true.
+	IncludeAliases *bool `json:"include_aliases,omitempty"`
+	// Restore the cluster state of the snapshot, the cluster default is false.
+	IncludeGlobalState *bool `json:"include_global_state,omitempty"`
+	// Alias attached to the restored indices once they are green, not part of the restore request.
+	Alias string `json:"alias,omitempty"`
+}
+
+// Returns the restore options of a request, the index settings are a JSON object and the ignored index
+// settings a comma separated list.
+func parseRestoreOptions(rename Rename, indexSettings string, ignoreIndexSettings string, includeAliases *bool, includeGlobalState *bool) (RestoreOptions, error) {
+	opts := RestoreOptions{Rename: rename, IncludeAliases: includeAliases, IncludeGlobalState: includeGlobalState}
+
+	var err error
+	if opts.IndexSettings, err = parseIndexSettings(indexSettings); err != nil {
+		return RestoreOptions{}, err
+	}
+	if opts.IgnoreIndexSettings, err = makeIgnoreIndexSettings(strings.Split(ignoreIndexSettings, ",")); err != nil {
+		return RestoreOptions{}, err
+	}
+	return opts, nil
+}
+
+// Returns true if indices restored with a and b end up the same on the cluster. Partial is set for each
+// restore from the state of its snapshot and is not compared.
+func sameRestoreOptions(a RestoreOptions, b RestoreOptions) bool {
+	a.Partial = false
+	b.Partial = false
+	return reflect.DeepEqual(a, b)
+}
+
+// Index settings that are set when an index is created and can't be changed or ignored by a restore.
+var fixedIndexSettings = []string{
+	"index.number_of_shards",
+	"index.version.created",
+	"index.uuid",
+	"index.creation_date",
+	"index.history.uuid",
+}
+
+// Returns the full name of an index setting, the index. prefix can be left out.
+func indexSettingName(name string) string {
+	if strings.HasPrefix(name, "index.") {
+		return name
+	}
+	return "index." + name
+}
+
+// Returns the index settings of a JSON object like {"number_of_replicas": 0}, empty if raw is empty.
+func parseIndexSettings(raw string) (map[string]string, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var settings map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &settings); err != nil {
+		return nil, errors.New(400, fmt.Sprintf("Invalid index_settings, expected a JSON object: %s", err))
+	}
+	return makeIndexSettings(settings)
+}
+
+// Validates index settings and flattens nested objects like {"routing": {"allocation": ...}} into full
+// setting names. Values are sent as strings, which the cluster accepts for every setting.
+func makeIndexSettings(settings map[string]interface{}) (map[string]string, error) {
+	if len(settings) == 0 {
+		return nil, nil
+	}
+
+	flat := make(map[string]string)
+	var flatten func(prefix string, value interface{}) error
+	flatten = func(prefix string, value interface{}) error {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for k, child := range v {
+				if err := flatten(prefix+"."+k, child); err != nil {
+					return err
+				}
+			}
+		case map[interface{}]interface{}:
+			// Nested maps decoded from YAML.
+			for k, child := range v {
+				if err := flatten(fmt.Sprintf("%s.%v", prefix, k), child); err != nil {
+					return err
+				}
+			}
+		case string:
+			flat[prefix] = v
+		case float64:
+			// JSON numbers decode as float64, %v would print 1000000 as 1e+06.
+			flat[prefix] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool, int, int64:
+			flat[prefix] = fmt.Sprintf("%v", v)
+		default:
+			return fmt.Errorf("index setting '%s' must be a string, number or boolean", indexSettingName(prefix))
+		}
+		return nil
+	}
+	for name, value := range settings {
+		if err := flatten(name, value); err != nil {
+			return nil, errors.New(400, fmt.Sprintf("Invalid index_settings: %s", err))
+		}
+	}
+
+	normalized := make(map[string]string, len(flat))
+	for name, value := range flat {
+		name = indexSettingName(name)
+		if stringInList(fixedIndexSettings, name) {
+			return nil, errors.New(400, fmt.Sprintf("Invalid index_settings: '%s' can't be changed on restore", name))
+		}
+		if name == "index.number_of_replicas" {
+			if n, err := strconv.Atoi(value); err != nil || n < 0 {
+				return nil, errors.New(400, fmt.Sprintf("Invalid index_settings: index.number_of_replicas must be a whole number of at least 0: %s", value))
+			}
+		}
+		normalized[name] = value
+	}
+	return normalized, nil
+}
+
+// Validates the names of ignored index settings, wildcards like index.routing.* are allowed.
+func makeIgnoreIndexSettings(names []string) ([]string, error) {
+	ignore := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		name = indexSettingName(name)
+		if stringInList(fixedIndexSettings, name) {
+			return nil, errors.New(400, fmt.Sprintf("Invalid ignore_index_settings: '%s' can't be ignored on restore", name))
+		}
+		ignore = append(ignore, name)
+	}
+	if len(ignore) == 0 {
+		return nil, nil
+	}
+	sort.Strings(ignore)
+	return ignore, nil
+}
+
+// Rename is the rename_pattern and rename_replacement of a restore. The replacement uses the syntax of
+// Elasticsearch, the one of Java's Matcher: $1 refers to a group of the pattern, ${name} to a named group
+// and \$ is a literal $.
+type Rename struct {
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement"`
+}
+
+// compiledRename is the pattern of a Rename compiled and its replacement in the syntax of regexp.Expand.
+type compiledRename struct {
+	re          *regexp.Regexp
+	replacement string
+}
+
+// Compiled renames keyed by Rename, so each pattern is compiled once.
+var (
+	compiledRenames   = make(map[Rename]compiledRename)
+	compiledRenamesMu sync.Mutex
+)
+
+// Returns the rename of a rename_pattern and rename_replacement, the zero Rename if both are empty.
+func parseRename(pattern string, replacement string) (Rename, error) {
+	if pattern == "" && replacement == "" {
+		return Rename{}, nil
+	}
+	if pattern == "" || replacement == "" {
+		return Rename{}, errors.New(400, "Both a rename_pattern and a rename_replacement are needed to rename restored indices")
+	}
+	r := Rename{Pattern: pattern, Replacement: replacement}
+	if _, err := r.compile(); err != nil {
+		return Rename{}, errors.New(400, err.Error())
+	}
+	return r, nil
+}
+
+// Returns the compiled pattern and replacement of the rename.
+func (r Rename) compile() (compiledRename, error) {
+	compiledRenamesMu.Lock()
+	defer compiledRenamesMu.Unlock()
+
+	if c, ok := compiledRenames[r]; ok {
+		return c, nil
+	}
+	re, err := regexp.Compile(r.Pattern)
+	if err != nil {
+		return compiledRename{}, fmt.Errorf("Invalid rename_pattern: %s", err)
+	}
+	replacement, err := expandTemplate(r.Replacement, re)
+	if err != nil {
+		return compiledRename{}, fmt.Errorf("Invalid rename_replacement: %s", err)
+	}
+	c := compiledRename{re: re, replacement: replacement}
+	compiledRenames[r] = c
+	return c, nil
+}
+
+// Converts a replacement in the syntax of Java's Matcher to a template of regexp.Expand. Group numbers
+// take as many digits as still name a group of the pattern, so $10 is group 1 followed by a 0 when the
+// pattern has fewer than 10 groups, and a backslash quotes the next character.
+func expandTemplate(replacement string, re *regexp.Regexp) (string, error) {
+	groups := re.NumSubexp()
+	var template bytes.Buffer
+	for i := 0; i < len(replacement); i++ {
+		c := replacement[i]
+		switch c {
+		case '\\':
+			i++
+			if i == len(replacement) {
+				return "", fmt.Errorf("character to be escaped is missing: %s", replacement)
+			}
+			if replacement[i] == '$' {
+				template.WriteString("$$")
+			} else {
+				template.WriteByte(replacement[i])
+			}
+		case '$':
+			i++
+			if i == len(replacement) {
+				return "", fmt.Errorf("illegal group reference, group index is missing: %s", replacement)
+			}
+			if replacement[i] == '{' {
+				end := strings.IndexByte(replacement[i:], '}')
+				if end < 0 {
+					return "", fmt.Errorf("named capturing group is missing trailing '}': %s", replacement)
+				}
+				name := replacement[i+1 : i+end]
+				if name == "" || !stringInList(re.SubexpNames()[1:], name) {
+					return "", fmt.Errorf("no group with name {%s}: %s", name, replacement)
+				}
+				template.WriteString("${" + name + "}")
+				i += end
+				continue
+			}
+			if replacement[i] < '0' || replacement[i] > '9' {
+				return "", fmt.Errorf("illegal group reference: %s", replacement)
+			}
+			group := int(replacement[i] - '0')
+			if group > groups {
+				return "", fmt.Errorf("no group %d: %s", group, replacement)
+			}
+			for i+1 < len(replacement) && replacement[i+1] >= '0' && replacement[i+1] <= '9' {
+				next := group*10 + int(replacement[i+1]-'0')
+				if next > groups {
+					break
+				}
+				group = next
+				i++
+			}
+			template.WriteString("${" + strconv.Itoa(group) + "}")
+		default:
+			template.WriteByte(c)
+		}
+	}
+	return template.String(), nil
+}
+
+// Returns the name an index is restored under, every match of the pattern is replaced like Java's
+// String.replaceAll does on the cluster.
+func (r Rename) apply(index string) string {
+	if r.Pattern == "" {
+		return index
+	}
+	c, err := r.compile()
+	if err != nil {
+		// Renames are validated before they are stored.
+		log.Println(fmt.Sprintf("ERROR: could not rename index: %s, %s", index, err))
+		return index
+	}
+
+	return c.re.ReplaceAllString(index, c.replacement)
+}
+
+// RestoreOptionsStore holds the restore options of every index queued for restore by esio, keyed by
+// repo/snap/index path, so workers restore it the way it was requested and know the name of the index on
+// the cluster.
+type RestoreOptionsStore struct {
+	options map[string]RestoreOptions
+	path    string
+	mu      sync.Mutex
+}
+
+var restoreOptionsStore *RestoreOptionsStore
+
+// NewRestoreOptionsStore returns an empty restore options store, options are persisted to path if it is not empty.
+func NewRestoreOptionsStore(path string) (*RestoreOptionsStore, error) {
+	s := &RestoreOptionsStore{options: make(map[string]RestoreOptions), path: path}
+
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("Error reading restore options file '%s': %s", path, err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &s.options); err != nil {
+			return nil, fmt.Errorf("Error decoding restore options file '%s': %s", path, err)
+		}
+	}
+
+	return s, nil
+}
+
+// Set sets the restore options of each index. Indices with restore options are tracked as restored by esio,
+// under the name given by their rename, until they are forgotten.
+func (s *RestoreOptionsStore) Set(indices []string, opts RestoreOptions) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	opts.Partial = false
+
+	changed := false
+	for _, indice := range indices {
+		if current, ok := s.options[indice]; ok && reflect.DeepEqual(current, opts) {
+			continue
+		}
+		s.options[indice] = opts
+		changed = true
+	}
+	if changed {
+		s.save()
+	}
+}
+
+// Get returns the restore options of an index.
+func (s *RestoreOptionsStore) Get(indice string) RestoreOptions {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.options[indice]
+}
+
+// Lookup returns the restore options of an index, false if the index is not tracked.
+func (s *RestoreOptionsStore) Lookup(indice string) (RestoreOptions, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	opts, ok := s.options[indice]
+	return opts, ok
+}
+
+// Tracked returns the indices of the list that have restore options, in order.
+func (s *RestoreOptionsStore) Tracked(indices []string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tracked := make([]string, 0, len(indices))
+	for _, indice := range indices {
+		if _, ok := s.options[indice]; ok {
+			tracked = append(tracked, indice)
+		}
+	}
+	return tracked
+}
+
+// Aliases returns the alias of every index that has one, keyed by repo/snap/index path.
+func (s *RestoreOptionsStore) Aliases() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	aliases := make(map[string]string)
+	for indice, opts := range s.options {
+		if opts.Alias != "" {
+			aliases[indice] = opts.Alias
+		}
+	}
+	return aliases
+}
+
+// Forget removes the restore options of each index.
+func (s *RestoreOptionsStore) Forget(indices []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := false
+	for _, indice := range indices {
+		if _, ok := s.options[indice]; ok {
+			delete(s.options, indice)
+			changed = true
+		}
+	}
+	if changed {
+		s.save()
+	}
+}
+
+// save writes all restore options to the restore options file, if one is configured. The caller must hold s.mu.
+func (s *RestoreOptionsStore) save() {
+	if s.path == "" {
+		return
+	}
+
+	data, err := json.Marshal(s.options)
+	if err == nil {
+		err = writeFileAtomic(s.path, data)
+	}
+	if err != nil {
+		log.Println(fmt.Sprintf("ERROR: could not write restore options file '%s': %s", s.path, err))
+	}
+}
+
+// Returns the name of the index of a repo/snap/index path on the cluster.
+func restoredName(indice string) string {
+	return restoreOptionsStore.Get(indice).Rename.apply(path.Base(indice))
+}
@@ -0,0 +1,162 @@
+package restapi
+
+import (
+	"reflect"
+	"testing"
+
+	errors "github.com/go-openapi/errors"
+)
+
+func TestParseIndexSettings(t *testing.T) {
+	tests := []struct {
+		raw   string
+		want  map[string]string
+		valid bool
+	}{
+		{"", nil, true},
+		{"{}", nil, true},
+		{`{"number_of_replicas": 0}`, map[string]string{"index.number_of_replicas": "0"}, true},
+		{`{"index.refresh_interval": "30s"}`, map[string]string{"index.refresh_interval": "30s"}, true},
+		{`{"max_result_window": 1000000}`, map[string]string{"index.max_result_window": "1000000"}, true},
+		{`{"max_result_window": 12345678901}`, map[string]string{"index.max_result_window": "12345678901"}, true},
+		{`{"indexing.slowlog.threshold.index.warn": 0.5}`, map[string]string{"index.indexing.slowlog.threshold.index.warn": "0.5"}, true},
+		{`{"blocks": {"write": true}}`, map[string]string{"index.blocks.write": "true"}, true},
+		{`{"routing": {"allocation": {"require": {"box_type": "warm"}}}}`, map[string]string{"index.routing.allocation.require.box_type": "warm"}, true},
+		{`{"index": {"number_of_replicas": 1}}`, map[string]string{"index.number_of_replicas": "1"}, true},
+		{`[]`, nil, false},
+		{`{"number_of_replicas": `, nil, false},
+		{`{"number_of_replicas": -1}`, nil, false},
+		{`{"number_of_replicas": 1.5}`, nil, false},
+		{`{"number_of_replicas": "two"}`, nil, false},
+		{`{"analysis": {"filter": ["lowercase"]}}`, nil, false},
+		{`{"number_of_shards": 1}`, nil, false},
+		{`{"index.number_of_shards": 1}`, nil, false},
+		{`{"index": {"uuid": "abc"}}`, nil, false},
+		{`{"version": {"created": 7170099}}`, nil, false},
+		{`{"creation_date": 1700000000000}`, nil, false},
+		{`{"history.uuid": "abc"}`, nil, false},
+	}
+
+	for _, tt := range tests {
+		got, err := parseIndexSettings(tt.raw)
+		if !tt.valid {
+			if err == nil {
+				t.Errorf("parseIndexSettings(%s) = %v, want an error", tt.raw, got)
+			} else if e, ok := err.(errors.Error); !ok || e.Code() != 400 {
+				t.Errorf("parseIndexSettings(%s) error = %v, want a 400", tt.raw, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseIndexSettings(%s) returned error: %s", tt.raw, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseIndexSettings(%s) = %v, want %v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestMakeIgnoreIndexSettings(t *testing.T) {
+	tests := []struct {
+		names []string
+		want  []string
+		valid bool
+	}{
+		{[]string{""}, nil, true},
+		{[]string{"refresh_interval", " index.routing.* "}, []string{"index.refresh_interval", "index.routing.*"}, true},
+		{[]string{"number_of_shards"}, nil, false},
+		{[]string{"refresh_interval", "index.uuid"}, nil, false},
+	}
+
+	for _, tt := range tests {
+		got, err := makeIgnoreIndexSettings(tt.names)
+		if !tt.valid {
+			if err == nil {
+				t.Errorf("makeIgnoreIndexSettings(%q) = %v, want an error", tt.names, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("makeIgnoreIndexSettings(%q) returned error: %s", tt.names, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("makeIgnoreIndexSettings(%q) = %v, want %v", tt.names, got, tt.want)
+		}
+	}
+}
+
+func TestRenameApply(t *testing.T) {
+	// Names as Elasticsearch restores them, with String.replaceAll.
+	tests := []struct {
+		pattern     string
+		replacement string
+		index       string
+		want        string
+	}{
+		{"(.+)", "$1_restored", "logs-v1", "logs-v1_restored"},
+		{"(.+)", "restored-$1", "logs-v1", "restored-logs-v1"},
+		{"logs-(.+)", "archive-$1", "logs-v1", "archive-v1"},
+		{"v(\\d)", "v$1$1", "logs-v1", "logs-v11"},
+		{"-", "_", "logs-v1-2016", "logs_v1_2016"},
+		{"(.+)", "$10", "logs-v1", "logs-v10"},
+		{"(.)(.)(.)(.)(.)(.)(.)(.)(.)(.)", "$10-$1", "abcdefghij", "j-a"},
+		{"(?P<name>.+)", "${name}-restored", "logs-v1", "logs-v1-restored"},
+		{"(.+)", "\\$1-$1", "logs-v1", "$1-logs-v1"},
+		{"(.+)", "\\\\$1", "logs-v1", "\\logs-v1"},
+		{"(.+)", "$0-x", "logs-v1", "logs-v1-x"},
+		{"nomatch", "x", "logs-v1", "logs-v1"},
+	}
+
+	for _, tt := range tests {
+		rename, err := parseRename(tt.pattern, tt.replacement)
+		if err != nil {
+			t.Errorf("parseRename(%q, %q) returned error: %s", tt.pattern, tt.replacement, err)
+			continue
+		}
+		if got := rename.apply(tt.index); got != tt.want {
+			t.Errorf("rename %q to %q of %s = %s, want %s", tt.pattern, tt.replacement, tt.index, got, tt.want)
+		}
+	}
+
+	if got := (Rename{}).apply("logs-v1"); got != "logs-v1" {
+		t.Errorf("no rename of logs-v1 = %s, want logs-v1", got)
+	}
+}
+
+func TestParseRename(t *testing.T) {
+	tests := []struct {
+		pattern     string
+		replacement string
+		valid       bool
+	}{
+		{"", "", true},
+		{"(.+)", "restored-$1", true},
+		{"(.+)", "", false},
+		{"", "restored-$1", false},
+		{"(.+", "restored-$1", false},
+		{"(.+)", "restored-$2", false},
+		{"(.+)", "restored-$", false},
+		{"(.+)", "restored-$x", false},
+		{"(.+)", "restored-${missing}", false},
+		{"(.+)", "restored-${}", false},
+		{"(?P<name>.+)", "restored-${name", false},
+		{"(.+)", "restored-\\", false},
+	}
+
+	for _, tt := range tests {
+		_, err := parseRename(tt.pattern, tt.replacement)
+		if !tt.valid {
+			if err == nil {
+				t.Errorf("parseRename(%q, %q) returned no error", tt.pattern, tt.replacement)
+			} else if e, ok := err.(errors.Error); !ok || e.Code() != 400 {
+				t.Errorf("parseRename(%q, %q) error = %v, want a 400", tt.pattern, tt.replacement, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseRename(%q, %q) returned error: %s", tt.pattern, tt.replacement, err)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package restapi
+
+import (
+	"sync"
+)
+
+// RestorePool bounds the number of indices being restored at once.
+// The limit can be changed at runtime, lowering it lets running restores finish but holds back new ones.
+type RestorePool struct {
+	max    int
+	active int
+	mu     sync.Mutex
+}
+
+// NewRestorePool returns a pool that allows up to max concurrent restores.
+func NewRestorePool(max int) *RestorePool {
+	if max < 1 {
+		max = 1
+	}
+	return &RestorePool{max: max}
+}
+
+// Max returns the maximum number of concurrent restores.
+func (p *RestorePool) Max() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.max
+}
+
+// SetMax changes the maximum number of concurrent restores.
+func (p *RestorePool) SetMax(max int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if max < 1 {
+		max = 1
+	}
+	p.max = max
+}
+
+// Active returns the number of restores currently running.
+func (p *RestorePool) Active() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.active
+}
+
+// acquire reserves a worker slot, it returns false if all slots are in use.
+func (p *RestorePool) acquire() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.active >= p.max {
+		return false
+	}
+	p.active++
+	return true
+}
+
+// release frees a worker slot reserved with acquire.
+func (p *RestorePool) release() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.active--
+}
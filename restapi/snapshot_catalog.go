@@ -0,0 +1,231 @@
+package restapi
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/danisla/esio/models"
+	errors "github.com/go-openapi/errors"
+)
+
+// Snapshot states that can be restored.
+const (
+	snapshotSuccess = "SUCCESS"
+	snapshotPartial = "PARTIAL"
+)
+
+// A repo is listed again when it has no SUCCESS snapshot of an index, unless it was refreshed within this time.
+const catalogMissRefresh = 30 * time.Second
+
+// catalogRepo is the snapshots of one repo as last listed from the cluster.
+type catalogRepo struct {
+	// Snapshots keyed by snapshot name.
+	snapshots map[string]Snapshot
+	// Number of distinct indices in the snapshots.
+	indices   int
+	refreshed time.Time
+	// Held while the repo is listed so a repo is only listed once at a time.
+	refreshMu sync.Mutex
+}
+
+// SnapshotCatalog holds the snapshots of every repo that has been looked up, keyed by repo name, so
+// requests are answered from memory instead of listing the repo for every index. Repos are listed on
+// their first lookup and refreshed in the background every snapshot refresh interval.
+type SnapshotCatalog struct {
+	repos map[string]*catalogRepo
+	mu    sync.RWMutex
+}
+
+var snapshotCatalog = NewSnapshotCatalog()
+
+func NewSnapshotCatalog() *SnapshotCatalog {
+	return &SnapshotCatalog{repos: make(map[string]*catalogRepo)}
+}
+
+// Snapshots returns the snapshots of a repo with the index whose name matches the selector, a snapshot
+// name or a glob like nightly-*, newest first. A repo that is not in the catalog yet, or has no SUCCESS
+// snapshot of the index, is listed from the cluster.
+func (c *SnapshotCatalog) Snapshots(ctx context.Context, repo string, selector string, index string) ([]Snapshot, error) {
+	if _, err := path.Match(selector, ""); err != nil {
+		return nil, errors.New(400, fmt.Sprintf("Invalid snapshot name or glob: %s", selector))
+	}
+
+	snapshots, loaded := c.lookup(repo, selector, index)
+	if loaded {
+		for _, snapshot := range snapshots {
+			if snapshot.State == snapshotSuccess {
+				return snapshots, nil
+			}
+		}
+	}
+
+	if err := c.refresh(ctx, repo, catalogMissRefresh); err != nil {
+		return nil, err
+	}
+	snapshots, _ = c.lookup(repo, selector, index)
+	return snapshots, nil
+}
+
+// State returns the state of a snapshot in the catalog, false if it is not in the catalog.
+func (c *SnapshotCatalog) State(repo string, name string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	r, ok := c.repos[repo]
+	if !ok {
+		return "", false
+	}
+	snapshot, ok := r.snapshots[name]
+	return snapshot.State, ok
+}
+
+// Returns the snapshots of the repo with the index that match the selector newest first, ties are broken
+// by name so the order is the same on every lookup. Returns false if the repo was never listed.
+func (c *SnapshotCatalog) lookup(repo string, selector string, index string) ([]Snapshot, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	snapshots := make([]Snapshot, 0)
+	r, ok := c.repos[repo]
+	if !ok || r.refreshed.IsZero() {
+		return snapshots, false
+	}
+
+	for name, snapshot := range r.snapshots {
+		if matched, _ := path.Match(selector, name); matched && stringInList(snapshot.Indices, index) {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	sort.Sort(newestFirst(snapshots))
+	return snapshots, true
+}
+
+// newestFirst sorts snapshots by start time, newest first, then by name in reverse.
+type newestFirst []Snapshot
+
+func (s newestFirst) Len() int      { return len(s) }
+func (s newestFirst) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s newestFirst) Less(i, j int) bool {
+	if s[i].StartTimeInMillis != s[j].StartTimeInMillis {
+		return s[i].StartTimeInMillis > s[j].StartTimeInMillis
+	}
+	return s[i].Snapshot > s[j].Snapshot
+}
+
+// Lists the snapshots of the repo from the cluster unless it was refreshed within maxAge.
+func (c *SnapshotCatalog) refresh(ctx context.Context, repo string, maxAge time.Duration) error {
+	c.mu.Lock()
+	r, ok := c.repos[repo]
+	if !ok {
+		r = &catalogRepo{}
+		c.repos[repo] = r
+	}
+	c.mu.Unlock()
+
+	r.refreshMu.Lock()
+	defer r.refreshMu.Unlock()
+
+	c.mu.RLock()
+	refreshed := r.refreshed
+	c.mu.RUnlock()
+	if !refreshed.IsZero() && time.Since(refreshed) < maxAge {
+		return nil
+	}
+
+	snapshots, err := backend.ListSnapshots(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	byName := make(map[string]Snapshot, len(snapshots))
+	indices := make(map[string]bool)
+	for _, snapshot := range snapshots {
+		byName[snapshot.Snapshot] = snapshot
+		for _, index := range snapshot.Indices {
+			indices[index] = true
+		}
+	}
+
+	c.mu.Lock()
+	r.snapshots, r.indices, r.refreshed = byName, len(indices), time.Now()
+	c.mu.Unlock()
+
+	log.Println(fmt.Sprintf("Refreshed snapshot catalog of repo: %s, snapshots: %d, indices: %d", repo, len(byName), len(indices)))
+	return nil
+}
+
+// Refresh lists the given repo, or every repo in the catalog if repo is empty, from the cluster.
+// Repos refreshed within maxAge are skipped.
+func (c *SnapshotCatalog) Refresh(ctx context.Context, repo string, maxAge time.Duration) error {
+	repos := []string{repo}
+	if repo == "" {
+		repos = c.repoNames()
+	}
+
+	var failed error
+	for _, name := range repos {
+		if err := c.refresh(ctx, name, maxAge); err != nil {
+			log.Println(fmt.Sprintf("WARN: could not refresh snapshot catalog of repo: %s, error: %s", name, err))
+			failed = err
+		}
+	}
+	return failed
+}
+
+// Returns the names of the repos in the catalog that have been listed, sorted.
+func (c *SnapshotCatalog) repoNames() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.repos))
+	for name, r := range c.repos {
+		if !r.refreshed.IsZero() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// List returns a summary of each repo in the catalog.
+func (c *SnapshotCatalog) List() []*models.SnapshotCatalogRepo {
+	names := c.repoNames()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	list := make([]*models.SnapshotCatalogRepo, 0, len(names))
+	for _, name := range names {
+		r := c.repos[name]
+		list = append(list, &models.SnapshotCatalogRepo{
+			Repo:      name,
+			Snapshots: int64(len(r.snapshots)),
+			Indices:   int64(r.indices),
+			Refreshed: r.refreshed.Unix(),
+		})
+	}
+	return list
+}
+
+// Refreshes the repos of the catalog in the background every snapshot refresh interval of the current
+// config, a zero interval disables background refreshes.
+func watchSnapshotCatalog() {
+	go func() {
+		for {
+			interval := currentConfig().snapshotRefresh
+			if interval <= 0 {
+				time.Sleep(configWatchInterval)
+				continue
+			}
+			time.Sleep(interval)
+
+			// Repos listed on a lookup since the last refresh are skipped.
+			snapshotCatalog.Refresh(context.Background(), "", interval)
+		}
+	}()
+}
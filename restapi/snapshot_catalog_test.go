@@ -0,0 +1,157 @@
+package restapi
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSnapshotCatalogOrder(t *testing.T) {
+	f := newFakeBackend()
+	defer useFakeBackend(f, &Config{})()
+
+	f.addSnapshot("logs", "nightly-1", snapshotSuccess, 1000, 10, "logs-a")
+	f.addSnapshot("logs", "nightly-3", snapshotSuccess, 3000, 10, "logs-a")
+	f.addSnapshot("logs", "nightly-2b", snapshotSuccess, 2000, 10, "logs-a")
+	f.addSnapshot("logs", "nightly-2a", snapshotSuccess, 2000, 10, "logs-a")
+	f.addSnapshot("logs", "nightly-4", snapshotSuccess, 4000, 10, "logs-b")
+	f.addSnapshot("logs", "weekly-5", snapshotSuccess, 5000, 10, "logs-a")
+
+	snapshots, err := snapshotCatalog.Snapshots(context.Background(), "logs", "nightly-*", "logs-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		names = append(names, snapshot.Snapshot)
+	}
+	// Newest first, the snapshots started at the same time in reverse name order.
+	if want := []string{"nightly-3", "nightly-2b", "nightly-2a", "nightly-1"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("snapshots %v, want %v", names, want)
+	}
+
+	resolved, err := resolveSnapshotIndex(context.Background(), "logs/nightly-*/logs-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "logs/nightly-3/logs-a"; resolved != want {
+		t.Errorf("resolved %s, want %s", resolved, want)
+	}
+	if f.listSnapshots != 1 {
+		t.Errorf("listed the repo %d times, want 1", f.listSnapshots)
+	}
+}
+
+func TestSnapshotCatalogAcceptPartial(t *testing.T) {
+	f := newFakeBackend()
+	defer useFakeBackend(f, &Config{})()
+
+	f.addSnapshot("logs", "nightly-1", snapshotPartial, 1000, 10, "logs-a")
+	f.addSnapshot("logs", "nightly-2", snapshotPartial, 2000, 10, "logs-a")
+	f.addSnapshot("logs", "nightly-3", "FAILED", 3000, 10, "logs-a")
+	f.addSnapshot("logs", "nightly-4", snapshotSuccess, 4000, 10, "logs-b")
+
+	if _, err := resolveSnapshotIndex(context.Background(), "logs/nightly-*/logs-a"); err == nil {
+		t.Errorf("resolved an index with only PARTIAL snapshots without --accept-partial")
+	}
+
+	c := currentConfig()
+	c.Indices.AcceptPartial = true
+	setConfig(c)
+
+	resolved, err := resolveSnapshotIndex(context.Background(), "logs/nightly-*/logs-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "logs/nightly-2/logs-a"; resolved != want {
+		t.Errorf("resolved %s, want %s", resolved, want)
+	}
+
+	// A SUCCESS snapshot is preferred to a newer PARTIAL one.
+	f.addSnapshot("logs", "nightly-0", snapshotSuccess, 500, 10, "logs-b")
+	f.addSnapshot("logs", "nightly-5", snapshotPartial, 5000, 10, "logs-b")
+	if err := snapshotCatalog.Refresh(context.Background(), "logs", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if resolved, err = resolveSnapshotIndex(context.Background(), "logs/nightly-*/logs-b"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "logs/nightly-4/logs-b"; resolved != want {
+		t.Errorf("resolved %s, want %s", resolved, want)
+	}
+}
+
+func TestSnapshotCatalogRefreshOnMiss(t *testing.T) {
+	f := newFakeBackend()
+	defer useFakeBackend(f, &Config{})()
+
+	f.addSnapshot("logs", "nightly-1", snapshotSuccess, 1000, 10, "logs-a")
+	ctx := context.Background()
+
+	if _, err := snapshotCatalog.Snapshots(ctx, "logs", "nightly-*", "logs-a"); err != nil {
+		t.Fatal(err)
+	}
+	if f.listSnapshots != 1 {
+		t.Fatalf("listed the repo %d times on the first lookup, want 1", f.listSnapshots)
+	}
+
+	// A hit is answered from the catalog.
+	f.addSnapshot("logs", "nightly-2", snapshotSuccess, 2000, 10, "logs-a", "logs-b")
+	snapshots, _ := snapshotCatalog.Snapshots(ctx, "logs", "nightly-*", "logs-a")
+	if f.listSnapshots != 1 || len(snapshots) != 1 {
+		t.Errorf("listed the repo %d times and found %d snapshots on a hit, want 1 and 1", f.listSnapshots, len(snapshots))
+	}
+
+	// A miss within catalogMissRefresh of the last listing is not listed again.
+	snapshots, _ = snapshotCatalog.Snapshots(ctx, "logs", "nightly-*", "logs-b")
+	if f.listSnapshots != 1 || len(snapshots) != 0 {
+		t.Errorf("listed the repo %d times and found %d snapshots on a recent miss, want 1 and 0", f.listSnapshots, len(snapshots))
+	}
+
+	// A miss after catalogMissRefresh lists the repo again and finds the new snapshot.
+	snapshotCatalog.repos["logs"].refreshed = time.Now().Add(-catalogMissRefresh - time.Second)
+	snapshots, _ = snapshotCatalog.Snapshots(ctx, "logs", "nightly-*", "logs-b")
+	if f.listSnapshots != 2 || len(snapshots) != 1 {
+		t.Errorf("listed the repo %d times and found %d snapshots on a stale miss, want 2 and 1", f.listSnapshots, len(snapshots))
+	}
+	if state, ok := snapshotCatalog.State("logs", "nightly-2"); !ok || state != snapshotSuccess {
+		t.Errorf("state of nightly-2 is %q, %t, want %q, true", state, ok, snapshotSuccess)
+	}
+}
+
+func TestResolveSnapshotIndexKeepsTrackedSnapshot(t *testing.T) {
+	f := newFakeBackend()
+	defer useFakeBackend(f, &Config{})()
+
+	f.addSnapshot("logs", "nightly-1", snapshotSuccess, 1000, 10, "logs-a", "logs-b")
+	ctx := context.Background()
+
+	resolved, err := resolveSnapshotIndices(ctx, []string{"logs/nightly-*/logs-a", "logs/nightly-*/logs-b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	restoreOptionsStore.Set(resolved[:1], RestoreOptions{})
+
+	// A newer snapshot is chosen for indices that were not restored, the restored one stays on its snapshot.
+	f.addSnapshot("logs", "nightly-2", snapshotSuccess, 2000, 10, "logs-a", "logs-b")
+	if err := snapshotCatalog.Refresh(ctx, "logs", 0); err != nil {
+		t.Fatal(err)
+	}
+	if resolved, err = resolveSnapshotIndices(ctx, []string{"logs/nightly-*/logs-a", "logs/nightly-*/logs-b"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"logs/nightly-1/logs-a", "logs/nightly-2/logs-b"}; !reflect.DeepEqual(resolved, want) {
+		t.Errorf("resolved %v, want %v", resolved, want)
+	}
+
+	// Once deleted the index resolves to the newest snapshot.
+	restoreOptionsStore.Forget([]string{"logs/nightly-1/logs-a"})
+	if resolved, err = resolveSnapshotIndices(ctx, []string{"logs/nightly-*/logs-a"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"logs/nightly-2/logs-a"}; !reflect.DeepEqual(resolved, want) {
+		t.Errorf("resolved %v after the delete, want %v", resolved, want)
+	}
+}
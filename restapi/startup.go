@@ -0,0 +1,99 @@
+package restapi
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	errors "github.com/go-openapi/errors"
+	strftime "github.com/hhkbp2/go-strftime"
+)
+
+// Exit codes of the startup validation, 1 is left for unexpected failures.
+const (
+	exitInvalidConfig      = 2
+	exitClusterUnavailable = 3
+	exitRepoNotFound       = 4
+	exitStateUnavailable   = 5
+	exitUnsupportedVersion = 6
+)
+
+// How long the cluster probe waits for ES to come up, and how often it tries.
+const clusterProbeTimeout = 30 * time.Second
+const clusterProbeInterval = 2 * time.Second
+
+// startupError is a failed startup check with every problem it found and the exit code of its kind.
+type startupError struct {
+	code     int
+	summary  string
+	problems []string
+}
+
+func (e *startupError) Error() string {
+	return fmt.Sprintf("%s: %s", e.summary, strings.Join(e.problems, "; "))
+}
+
+// report returns the error with one problem per line.
+func (e *startupError) report() string {
+	return fmt.Sprintf("%s:\n  - %s", e.summary, strings.Join(e.problems, "\n  - "))
+}
+
+// Logs the startup error and exits with its code.
+func exitOnStartupError(err error) {
+	if e, ok := err.(*startupError); ok {
+		log.Println(fmt.Sprintf("ERROR: %s", e.report()))
+		os.Exit(e.code)
+	}
+	log.Println(fmt.Sprintf("ERROR: %s", err))
+	os.Exit(1)
+}
+
+// Probes the cluster and the snapshot repo of the default repo pattern before the server starts.
+func validateStartup(c *Config) error {
+	version, err := probeCluster()
+	if version.Number == "" {
+		return &startupError{exitClusterUnavailable, "Elasticsearch cluster unavailable", []string{fmt.Sprintf("%s: %s", c.Elasticsearch.Host, err)}}
+	}
+	if _, err := adapterFor(version); err != nil {
+		return &startupError{exitUnsupportedVersion, "Unsupported cluster version", []string{fmt.Sprintf("%s: %s", c.Elasticsearch.Host, err)}}
+	}
+
+	// Patterns can roll repos by year or month, the repo of the current period may not be registered yet
+	// when a new period starts so only a missing fixed repo stops the server.
+	repoPattern := strings.Split(c.Indices.RepoPattern, "/")[0]
+	repo := strftime.Format(repoPattern, time.Now().In(c.location))
+	if err := backend.RepoExists(context.Background(), repo); err != nil {
+		if repo == repoPattern {
+			return &startupError{exitRepoNotFound, "Snapshot repository unavailable", []string{fmt.Sprintf("%s: %s", repo, err)}}
+		}
+		log.Println(fmt.Sprintf("WARN: snapshot repository of the current period is unavailable, repo pattern: %s, repo: %s, error: %s", repoPattern, repo, err))
+	}
+
+	return nil
+}
+
+// Returns the version of the cluster, retrying until clusterProbeTimeout for clusters that are still starting.
+func probeCluster() (ClusterVersion, error) {
+	deadline := time.Now().Add(clusterProbeTimeout)
+	for {
+		version, err := backend.Version(context.Background())
+		if version.Number != "" || err == nil || time.Now().After(deadline) {
+			return version, err
+		}
+		log.Println(fmt.Sprintf("Waiting for Elasticsearch: %s", err))
+		time.Sleep(clusterProbeInterval)
+	}
+}
+
+// Checks that a repo pattern has the three repo/snap/index path segments.
+func validateRepoPattern(pattern string) error {
+	parts := strings.Split(pattern, "/")
+	if len(parts) != 3 || path.Clean(pattern) != pattern || parts[0] == "" {
+		return errors.New(400, fmt.Sprintf("Invalid repo pattern, expected repo/snap/index: %s", pattern))
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package restapi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateStartupRepo(t *testing.T) {
+	f := newFakeBackend()
+	f.addSnapshot("logs", "nightly-1", snapshotSuccess, 1000, 10, "logs-v1-2016-04-07")
+	f.addSnapshot("logs-2015", "nightly-1", snapshotSuccess, 1000, 10, "logs-v1-2015-12-31")
+	c := &Config{location: time.UTC}
+	defer useFakeBackend(f, c)()
+
+	tests := []struct {
+		repoPattern string
+		code        int
+	}{
+		{"logs/nightly-*/logs-v1-%Y-%m-%d", 0},
+		{"archive/nightly-*/logs-v1-%Y-%m-%d", exitRepoNotFound},
+		// The repo of the current year is not registered yet, only last year's is.
+		{"logs-%Y/nightly-*/logs-v1-%Y-%m-%d", 0},
+	}
+	for _, tt := range tests {
+		c.Indices.RepoPattern = tt.repoPattern
+		err := validateStartup(c)
+		code := 0
+		if e, ok := err.(*startupError); ok {
+			code = e.code
+		} else if err != nil {
+			code = 1
+		}
+		if code != tt.code {
+			t.Errorf("%s: validateStartup() = %v, want exit code %d", tt.repoPattern, err, tt.code)
+		}
+	}
+}
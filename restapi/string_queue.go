@@ -1,8 +1,9 @@
 package restapi
 
 import (
-	// "log"
-	// "fmt"
+	"fmt"
+	"log"
+	"sync"
 )
 
 type Node struct {
@@ -10,16 +11,157 @@ type Node struct {
 }
 
 // Queue is a basic FIFO queue based on a circular list that resizes as needed.
+// It is safe for concurrent use by the HTTP handlers and the queue workers.
 type Queue struct {
 	nodes []*Node
 	size  int
 	head  int
 	tail  int
 	count int
+	mu    sync.Mutex
+
+	journal *QueueJournal
 }
 
 // Push adds a node to the queue.
 func (q *Queue) Push(n *Node) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.push(n)
+	q.sync()
+}
+
+// Pop removes and returns a node from the queue in first to last order.
+func (q *Queue) Pop() *Node {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	node := q.pop()
+	if node == nil {
+		return nil
+	}
+	if q.journal != nil {
+		q.journal.begin(node.Value)
+	}
+	q.sync()
+	return node
+}
+
+// PopGroup removes and returns the first node for which ready(value) is true along with every other
+// node in the queue for which same(first, other) is true. Nodes are returned, and the remaining nodes
+// kept, in first to last order. It returns nil if no node is ready.
+func (q *Queue) PopGroup(ready func(string) bool, same func(a, b string) bool) []*Node {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var first *Node
+	group := make([]*Node, 0)
+	rest := make([]*Node, 0, q.count)
+	for q.count > 0 {
+		node := q.pop()
+		if first == nil && ready(node.Value) {
+			first = node
+		}
+		if first != nil && same(first.Value, node.Value) {
+			group = append(group, node)
+		} else {
+			rest = append(rest, node)
+		}
+	}
+
+	for _, node := range rest {
+		q.push(node)
+	}
+
+	if first == nil {
+		return nil
+	}
+
+	if q.journal != nil {
+		for _, node := range group {
+			q.journal.begin(node.Value)
+		}
+	}
+	q.sync()
+
+	return group
+}
+
+// Remove deletes the first node with the given value from the queue, it returns false if none was found.
+func (q *Queue) Remove(target string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	found := false
+	n := q.count
+	for i := 0; i < n; i++ {
+		node := q.pop()
+		if !found && node.Value == target {
+			found = true
+			continue
+		}
+		q.push(node)
+	}
+
+	if found {
+		q.sync()
+	}
+	return found
+}
+
+// Done marks a node returned by Pop as finished so it is no longer journaled as in flight.
+func (q *Queue) Done(n *Node) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.journal == nil {
+		return
+	}
+	q.journal.finish(n.Value)
+	q.sync()
+}
+
+// Len returns the number of nodes in the queue.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.count
+}
+
+// Snapshot returns the values currently in the queue in first to last order.
+func (q *Queue) Snapshot() []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.values()
+}
+
+// Contains searches the nodes currently in the queue for given target string
+func (q *Queue) Contains(target string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i := 0; i < q.count; i++ {
+		if q.nodes[(q.head+i)%len(q.nodes)].Value == target {
+			return true
+		}
+	}
+	return false
+}
+
+// attach sets the journal the queue contents are written to on every change.
+func (q *Queue) attach(j *QueueJournal) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.journal = j
+	q.sync()
+}
+
+// push adds a node to the queue, the caller must hold q.mu.
+func (q *Queue) push(n *Node) {
 	if q.head == q.tail && q.count > 0 {
 		nodes := make([]*Node, len(q.nodes)+q.size)
 		copy(nodes, q.nodes[q.head:])
@@ -33,33 +175,42 @@ func (q *Queue) Push(n *Node) {
 	q.count++
 }
 
-// Pop removes and returns a node from the queue in first to last order.
-func (q *Queue) Pop() *Node {
+// pop removes and returns the first node, the caller must hold q.mu.
+func (q *Queue) pop() *Node {
 	if q.count == 0 {
 		return nil
 	}
 	node := q.nodes[q.head]
+	q.nodes[q.head] = nil
 	q.head = (q.head + 1) % len(q.nodes)
 	q.count--
 	return node
 }
 
-// Contains searches nodes for given target string
-func (q *Queue) Contains(target string) bool {
-	if q.count == 0 {
-		return false
+// values returns the queued values in first to last order, the caller must hold q.mu.
+func (q *Queue) values() []string {
+	values := make([]string, 0, q.count)
+	for i := 0; i < q.count; i++ {
+		values = append(values, q.nodes[(q.head+i)%len(q.nodes)].Value)
 	}
+	return values
+}
 
-  for _, n := range q.nodes {
-		if n.Value == target {
-			return true
-		}
+// sync writes the queue contents to the journal, if one is attached. The caller must hold q.mu.
+func (q *Queue) sync() {
+	if q.journal == nil {
+		return
+	}
+	if err := q.journal.save(q.values()); err != nil {
+		log.Println(fmt.Sprintf("ERROR: could not write queue journal '%s': %s", q.journal.path, err))
 	}
-	return false
 }
 
 // NewQueue returns a new queue with the given initial size.
 func NewQueue(size int) *Queue {
+	if size < 1 {
+		size = 1
+	}
 	return &Queue{
 		nodes: make([]*Node, size),
 		size:  size,
@@ -0,0 +1,163 @@
+package restapi
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestQueueOrder(t *testing.T) {
+	q := NewQueue(1)
+	for i := 0; i < 5; i++ {
+		q.Push(&Node{fmt.Sprintf("repo/snap/index-%d", i)})
+	}
+
+	if !q.Remove("repo/snap/index-2") {
+		t.Fatalf("Remove of a queued value returned false")
+	}
+	if q.Remove("repo/snap/index-2") {
+		t.Fatalf("Remove of a removed value returned true")
+	}
+	if q.Contains("repo/snap/index-2") || !q.Contains("repo/snap/index-3") {
+		t.Fatalf("Contains does not match the queue: %v", q.Snapshot())
+	}
+
+	want := []string{"repo/snap/index-0", "repo/snap/index-1", "repo/snap/index-3", "repo/snap/index-4"}
+	if got := q.Snapshot(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Snapshot() = %v, want %v", got, want)
+	}
+	if got := q.Len(); got != len(want) {
+		t.Fatalf("Len() = %d, want %d", got, len(want))
+	}
+
+	for _, value := range want {
+		if node := q.Pop(); node == nil || node.Value != value {
+			t.Fatalf("Pop() = %v, want %s", node, value)
+		}
+	}
+	if node := q.Pop(); node != nil {
+		t.Fatalf("Pop() of an empty queue = %v, want nil", node)
+	}
+}
+
+func TestQueuePopGroup(t *testing.T) {
+	q := NewQueue(2)
+	for _, value := range []string{"repo/a/index-1", "repo/b/index-1", "repo/a/index-2", "repo/b/index-2", "repo/a/index-3"} {
+		q.Push(&Node{value})
+	}
+
+	ready := func(value string) bool { return path.Dir(value) == "repo/b" }
+	same := func(a, b string) bool { return path.Dir(a) == path.Dir(b) }
+
+	group := q.PopGroup(ready, same)
+	got := make([]string, 0, len(group))
+	for _, node := range group {
+		got = append(got, node.Value)
+	}
+	if want := []string{"repo/b/index-1", "repo/b/index-2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("PopGroup() = %v, want %v", got, want)
+	}
+	if want := []string{"repo/a/index-1", "repo/a/index-2", "repo/a/index-3"}; !reflect.DeepEqual(q.Snapshot(), want) {
+		t.Fatalf("Snapshot() after PopGroup = %v, want %v", q.Snapshot(), want)
+	}
+
+	if group := q.PopGroup(ready, same); group != nil {
+		t.Fatalf("PopGroup() without a ready node = %v, want nil", group)
+	}
+}
+
+// Run with go test -race, every value pushed has to come out of the queue exactly once.
+func TestQueueConcurrent(t *testing.T) {
+	const producers = 8
+	const perProducer = 500
+
+	q := NewQueue(4)
+
+	var mu sync.Mutex
+	seen := make(map[string]int)
+	take := func(value string) {
+		mu.Lock()
+		defer mu.Unlock()
+		seen[value]++
+	}
+
+	var pushers sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		pushers.Add(1)
+		go func(p int) {
+			defer pushers.Done()
+			for i := 0; i < perProducer; i++ {
+				q.Push(&Node{fmt.Sprintf("repo/snap-%d/index-%d", p, i)})
+			}
+		}(p)
+	}
+
+	done := make(chan struct{})
+	var workers sync.WaitGroup
+	worker := func(work func()) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					work()
+				}
+			}
+		}()
+	}
+
+	// Restore and delete workers.
+	worker(func() {
+		if node := q.Pop(); node != nil {
+			take(node.Value)
+		}
+	})
+	worker(func() {
+		ready := func(string) bool { return true }
+		same := func(a, b string) bool { return path.Dir(a) == path.Dir(b) }
+		for _, node := range q.PopGroup(ready, same) {
+			take(node.Value)
+		}
+	})
+	// Cancelled jobs.
+	worker(func() {
+		for _, value := range q.Snapshot() {
+			if q.Remove(value) {
+				take(value)
+			}
+			break
+		}
+	})
+	// Status requests.
+	worker(func() {
+		values := q.Snapshot()
+		if len(values) > 0 {
+			q.Contains(values[len(values)-1])
+		}
+		if q.Len() < 0 {
+			t.Errorf("Len() is negative")
+		}
+	})
+
+	pushers.Wait()
+	for q.Len() > 0 {
+		runtime.Gosched()
+	}
+	close(done)
+	workers.Wait()
+
+	if len(seen) != producers*perProducer {
+		t.Fatalf("%d values came out of the queue, want %d", len(seen), producers*perProducer)
+	}
+	for value, n := range seen {
+		if n != 1 {
+			t.Fatalf("%s came out of the queue %d times, want 1", value, n)
+		}
+	}
+}
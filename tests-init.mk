@@ -17,7 +17,8 @@ TEST_INDICES_YEAR := test-v1-2013 test-v1-2014 test-v1-2015 test-v1-2016
 
 TEST_INDICES := $(TEST_INDICES_DOY) $(TEST_INDICES_MONTH) $(TEST_INDICES_YEAR)
 
-TEST_DEPS := $(ES_DEPS) start-server wait-server repo-$(TEST_REPO)
+# The server checks the snapshot repo on startup, so it is created first.
+TEST_DEPS := $(ES_DEPS) repo-$(TEST_REPO) start-server wait-server
 
 repo-%: $(ES_DEPS)
 	@if curl -sf http://localhost:9200/_snapshot/$* > /dev/null; then echo "Snapshot repo exists: $*"; exit 0; fi ; \
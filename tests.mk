@@ -5,6 +5,9 @@ include tests-init.mk
 TEST_REPO_PATTERN_DAILY := test%2Fdaily%2Ftest-v1-%25Y_%25j
 TEST_REPO_PATTERN_MONTHLY := test%2Fmonthly%2Ftest-v1-%25Y_%25m
 
+# Datasets loaded by the test server
+export DATASETS := datasets.example.yml
+
 # 2016-04-07T00:00:00Z
 DOY_START_TS := 1459987200
 # 2016-04-10T12:00:00Z
@@ -15,7 +18,7 @@ MON_START_TS := 1472688000
 # 2016-12-28T00:00:00Z
 MON_END_TS := 1482883200
 
-test: test-invalid-resolution test-out-of-range test-1-day-offline test-3-day-offline test-1-month-offline test-3-month-offline test-1-day-restore test-1-day-delete test-3-day-restore test-3-day-delete
+test: test-invalid-resolution test-invalid-timezone test-invalid-time test-date-range test-duration-resolution test-out-of-range test-1-day-offline test-3-day-offline test-1-month-offline test-3-month-offline test-day-boundary test-1-day-restore test-1-day-delete test-3-day-restore test-3-day-delete test-job-not-found test-job-location test-invalid-ttl test-invalid-rename test-invalid-index-settings test-invalid-alias test-datasets test-dataset-not-found test-dataset-offline test-snapshot-catalog-refresh test-startup-invalid-config test-startup-missing-repo
 	@echo "All tests PASSED"
 
 ###
@@ -34,12 +37,42 @@ test-%-month-offline: $(TEST_DEPS)
 	@if [ "$(RES)" != "$(EXP_RES)" ]; then echo "TEST ERROR: Expected status code '$(EXP_RES)' but saw: '$(RES)'" ; exit 1; fi
 	@echo "PASSED: $@ "
 
+test-day-boundary: $(TEST_DEPS)
+	$(eval EXP_LEN := 2)
+	$(eval LEN := $(shell curl --silent -XGET "http://$(APP_HOST):$(APP_PORT)/$(shell echo $(DOY_START_TS) + 3600*23 | bc)/$(shell echo $(DOY_START_TS) + 3600*25 | bc)?repo_pattern=$(TEST_REPO_PATTERN_DAILY)&resolution=day" | jq '.pending | length'))
+	@if [ "$(LEN)" != "$(EXP_LEN)" ]; then echo "TEST ERROR: Expected '$(EXP_LEN)' indices overlapping range but saw: '$(LEN)'" ; exit 1; fi
+	@echo "PASSED: $@ "
+
 test-invalid-resolution: $(TEST_DEPS)
-	$(eval EXP_RES := 400)
+	$(eval EXP_RES := 422)
 	$(eval RES := $(shell curl --silent --output /dev/stderr --write-out "%{http_code}" -XGET "http://$(APP_HOST):$(APP_PORT)/$(MON_START_TS)/$(MON_END_TS)?resolution=foo"))
 	@if [ "$(RES)" != "$(EXP_RES)" ]; then echo "TEST ERROR: Expected status code '$(EXP_RES)' but saw: '$(RES)'" ; exit 1; fi
 	@echo "PASSED: $@ "
 
+test-invalid-timezone: $(TEST_DEPS)
+	$(eval EXP_RES := 400)
+	$(eval RES := $(shell curl --silent --output /dev/stderr --write-out "%{http_code}" -XGET "http://$(APP_HOST):$(APP_PORT)/$(MON_START_TS)/$(MON_END_TS)?tz=Nowhere%2FFoo"))
+	@if [ "$(RES)" != "$(EXP_RES)" ]; then echo "TEST ERROR: Expected status code '$(EXP_RES)' but saw: '$(RES)'" ; exit 1; fi
+	@echo "PASSED: $@ "
+
+test-invalid-time: $(TEST_DEPS)
+	$(eval EXP_RES := 400)
+	$(eval RES := $(shell curl --silent --output /dev/stderr --write-out "%{http_code}" -XGET "http://$(APP_HOST):$(APP_PORT)/yesterday/now"))
+	@if [ "$(RES)" != "$(EXP_RES)" ]; then echo "TEST ERROR: Expected status code '$(EXP_RES)' but saw: '$(RES)'" ; exit 1; fi
+	@echo "PASSED: $@ "
+
+test-date-range: $(TEST_DEPS)
+	$(eval EXP_RES := 404)
+	$(eval RES := $(shell curl --silent --output /dev/stderr --write-out "%{http_code}" -XGET "http://$(APP_HOST):$(APP_PORT)/2016-04-07/2016-04-09T23:59:59Z?repo_pattern=$(TEST_REPO_PATTERN_DAILY)&resolution=day"))
+	@if [ "$(RES)" != "$(EXP_RES)" ]; then echo "TEST ERROR: Expected status code '$(EXP_RES)' but saw: '$(RES)'" ; exit 1; fi
+	@echo "PASSED: $@ "
+
+test-duration-resolution: $(TEST_DEPS)
+	$(eval EXP_RES := 404)
+	$(eval RES := $(shell curl --silent --output /dev/stderr --write-out "%{http_code}" -XGET "http://$(APP_HOST):$(APP_PORT)/$(DOY_START_TS)/$(shell echo $(DOY_START_TS) + 3600*24*3 | bc)?repo_pattern=$(TEST_REPO_PATTERN_DAILY)&resolution=12h"))
+	@if [ "$(RES)" != "$(EXP_RES)" ]; then echo "TEST ERROR: Expected status code '$(EXP_RES)' but saw: '$(RES)'" ; exit 1; fi
+	@echo "PASSED: $@ "
+
 test-missing-snapsnot:
 	$(eval EXP_RES := 416)
 	$(eval RES := $(shell curl --silent --output /dev/stderr --write-out "%{http_code}" -XGET "http://$(APP_HOST):$(APP_PORT)/$(MON_START_TS)/$(MON_END_TS)?resolution=day&repo_pattern=test-foo%2Fdaily%2Ftest-v1-%25Y_%25j"))
@@ -60,13 +93,13 @@ clean-test: clean-repo clean-template
 
 test-%-day-restore: $(TEST_DEPS)
 	$(eval EXP_RES := 202)
-	$(eval RES := $(shell curl --silent --output /dev/stderr --write-out "%{http_code}" -XPOST "http://$(APP_HOST):$(APP_PORT)/$(DOY_START_TS)/$(shell echo $(DOY_START_TS) + 3600*24*$* | bc)?repo_pattern=$(TEST_REPO_PATTERN_DAILY)&resolution=day"))
+	$(eval RES := $(shell curl --silent --output /dev/stderr --write-out "%{http_code}" -XPOST "http://$(APP_HOST):$(APP_PORT)/$(DOY_START_TS)/$(shell echo $(DOY_START_TS) + 3600*24*$* - 1 | bc)?repo_pattern=$(TEST_REPO_PATTERN_DAILY)&resolution=day"))
 	@if [ "$(RES)" != "$(EXP_RES)" ]; then echo "TEST ERROR: Expected status code '$(EXP_RES)' but saw: '$(RES)'" ; exit 1; fi
 	@echo "TEST: Verifying restore is queued" ; \
-	while [[ `curl --silent -XGET "http://$(APP_HOST):$(APP_PORT)/$(DOY_START_TS)/$(shell echo $(DOY_START_TS) + 3600*24*$* | bc)?repo_pattern=$(TEST_REPO_PATTERN_DAILY)&resolution=day" | jq '.restoring | length'` -ne $* ]]; do \
+	while [[ `curl --silent -XGET "http://$(APP_HOST):$(APP_PORT)/$(DOY_START_TS)/$(shell echo $(DOY_START_TS) + 3600*24*$* - 1 | bc)?repo_pattern=$(TEST_REPO_PATTERN_DAILY)&resolution=day" | jq '.restoring | length'` -ne $* ]]; do \
 		echo "TEST: Wating for $* indices to queue"; sleep 2 ; done
 	@echo "TEST: Verifying index was restored" ; \
-	while [[ `curl --silent -XGET "http://$(APP_HOST):$(APP_PORT)/$(DOY_START_TS)/$(shell echo $(DOY_START_TS) + 3600*24*$* | bc)?repo_pattern=$(TEST_REPO_PATTERN_DAILY)&resolution=day" | jq '.ready | length'` -ne $* ]]; do \
+	while [[ `curl --silent -XGET "http://$(APP_HOST):$(APP_PORT)/$(DOY_START_TS)/$(shell echo $(DOY_START_TS) + 3600*24*$* - 1 | bc)?repo_pattern=$(TEST_REPO_PATTERN_DAILY)&resolution=day" | jq '.ready | length'` -ne $* ]]; do \
 		echo "TEST: Wating for $* indices to come online"; sleep 2; done
 	@echo "PASSED: $@ "
 
@@ -76,14 +109,104 @@ test-%-day-restore: $(TEST_DEPS)
 
 test-%-day-delete: $(TEST_DEPS)
 	$(eval EXP_RES := 202)
-	$(eval ROUTE := "http://$(APP_HOST):$(APP_PORT)/$(DOY_START_TS)/$(shell echo $(DOY_START_TS) + 3600*24*$* | bc)?repo_pattern=$(TEST_REPO_PATTERN_DAILY)&resolution=day")
+	$(eval ROUTE := "http://$(APP_HOST):$(APP_PORT)/$(DOY_START_TS)/$(shell echo $(DOY_START_TS) + 3600*24*$* - 1 | bc)?repo_pattern=$(TEST_REPO_PATTERN_DAILY)&resolution=day")
 	@echo $(ROUTE)
 	$(eval RES := $(shell curl --silent --output /dev/stderr --write-out "%{http_code}" -XDELETE $(ROUTE)))
 	@if [ "$(RES)" != "$(EXP_RES)" ]; then echo "TEST ERROR: Expected status code '$(EXP_RES)' but saw: '$(RES)'" ; exit 1; fi
 	@echo "TEST: Verifying delete is queued" ; \
-	while [[ `curl --silent -XGET "http://$(APP_HOST):$(APP_PORT)/$(DOY_START_TS)/$(shell echo $(DOY_START_TS) + 3600*24*$* | bc)?repo_pattern=$(TEST_REPO_PATTERN_DAILY)&resolution=day" | jq '.deleting | length'` -ne $* ]]; do \
+	while [[ `curl --silent -XGET "http://$(APP_HOST):$(APP_PORT)/$(DOY_START_TS)/$(shell echo $(DOY_START_TS) + 3600*24*$* - 1 | bc)?repo_pattern=$(TEST_REPO_PATTERN_DAILY)&resolution=day" | jq '.deleting | length'` -ne $* ]]; do \
 		echo "TEST: Wating for $* indices to queue"; sleep 2 ; done
 	@echo "TEST: Verifying indices were deleted" ; \
-	while [[ `curl --silent -XGET "http://$(APP_HOST):$(APP_PORT)/$(DOY_START_TS)/$(shell echo $(DOY_START_TS) + 3600*24*$* | bc)?repo_pattern=$(TEST_REPO_PATTERN_DAILY)&resolution=day" | jq '.pending | length'` -ne $* ]]; do \
+	while [[ `curl --silent -XGET "http://$(APP_HOST):$(APP_PORT)/$(DOY_START_TS)/$(shell echo $(DOY_START_TS) + 3600*24*$* - 1 | bc)?repo_pattern=$(TEST_REPO_PATTERN_DAILY)&resolution=day" | jq '.pending | length'` -ne $* ]]; do \
 		echo "TEST: Wating for $* indices to be deleted"; sleep 2; done
 	@echo "PASSED: $@ "
+
+###
+### Tests for /jobs
+###
+
+test-job-not-found: $(TEST_DEPS)
+	$(eval EXP_RES := 404)
+	$(eval RES := $(shell curl --silent --output /dev/stderr --write-out "%{http_code}" -XGET "http://$(APP_HOST):$(APP_PORT)/jobs/does-not-exist"))
+	@if [ "$(RES)" != "$(EXP_RES)" ]; then echo "TEST ERROR: Expected status code '$(EXP_RES)' but saw: '$(RES)'" ; exit 1; fi
+	@echo "PASSED: $@ "
+
+test-job-location: $(TEST_DEPS)
+	$(eval EXP_RES := 200)
+	$(eval LOCATION := $(shell curl --silent --output /dev/null --dump-header - -XDELETE "http://$(APP_HOST):$(APP_PORT)/$(DOY_START_TS)/$(shell echo $(DOY_START_TS) + 3600*24 | bc)?repo_pattern=$(TEST_REPO_PATTERN_DAILY)&resolution=day" | awk 'tolower($$1) == "location:" {print $$2}' | tr -d '\r'))
+	@if [ -z "$(LOCATION)" ]; then echo "TEST ERROR: No Location header returned" ; exit 1; fi
+	$(eval RES := $(shell curl --silent --output /dev/stderr --write-out "%{http_code}" -XGET "http://$(APP_HOST):$(APP_PORT)$(LOCATION)"))
+	@if [ "$(RES)" != "$(EXP_RES)" ]; then echo "TEST ERROR: Expected status code '$(EXP_RES)' but saw: '$(RES)'" ; exit 1; fi
+	@echo "PASSED: $@ "
+
+###
+### Tests for TTL
+###
+
+test-invalid-ttl: $(TEST_DEPS)
+	$(eval EXP_RES := 400)
+	$(eval RES := $(shell curl --silent --output /dev/stderr --write-out "%{http_code}" -XPOST "http://$(APP_HOST):$(APP_PORT)/$(DOY_START_TS)/$(shell echo $(DOY_START_TS) + 3600*24 | bc)?repo_pattern=$(TEST_REPO_PATTERN_DAILY)&resolution=day&ttl=forever"))
+	@if [ "$(RES)" != "$(EXP_RES)" ]; then echo "TEST ERROR: Expected status code '$(EXP_RES)' but saw: '$(RES)'" ; exit 1; fi
+	@echo "PASSED: $@ "
+
+test-invalid-rename: $(TEST_DEPS)
+	$(eval EXP_RES := 400)
+	$(eval RES := $(shell curl --silent --output /dev/stderr --write-out "%{http_code}" -XPOST "http://$(APP_HOST):$(APP_PORT)/$(DOY_START_TS)/$(shell echo $(DOY_START_TS) + 3600*24 | bc)?repo_pattern=$(TEST_REPO_PATTERN_DAILY)&resolution=day&rename_pattern=%28"))
+	@if [ "$(RES)" != "$(EXP_RES)" ]; then echo "TEST ERROR: Expected status code '$(EXP_RES)' but saw: '$(RES)'" ; exit 1; fi
+	@echo "PASSED: $@ "
+
+test-invalid-index-settings: $(TEST_DEPS)
+	$(eval EXP_RES := 400)
+	$(eval RES := $(shell curl --silent --output /dev/stderr --write-out "%{http_code}" -XPOST "http://$(APP_HOST):$(APP_PORT)/$(DOY_START_TS)/$(shell echo $(DOY_START_TS) + 3600*24 | bc)?repo_pattern=$(TEST_REPO_PATTERN_DAILY)&resolution=day&index_settings=%7B%22number_of_shards%22%3A2%7D"))
+	@if [ "$(RES)" != "$(EXP_RES)" ]; then echo "TEST ERROR: Expected status code '$(EXP_RES)' but saw: '$(RES)'" ; exit 1; fi
+	@echo "PASSED: $@ "
+
+test-invalid-alias: $(TEST_DEPS)
+	$(eval EXP_RES := 400)
+	$(eval RES := $(shell curl --silent --output /dev/stderr --write-out "%{http_code}" -XPOST "http://$(APP_HOST):$(APP_PORT)/$(DOY_START_TS)/$(shell echo $(DOY_START_TS) + 3600*24 | bc)?repo_pattern=$(TEST_REPO_PATTERN_DAILY)&resolution=day&alias=Restored%20Logs"))
+	@if [ "$(RES)" != "$(EXP_RES)" ]; then echo "TEST ERROR: Expected status code '$(EXP_RES)' but saw: '$(RES)'" ; exit 1; fi
+	@echo "PASSED: $@ "
+
+###
+### Tests for /datasets
+###
+
+test-datasets: $(TEST_DEPS)
+	$(eval EXP_LEN := 2)
+	$(eval LEN := $(shell curl --silent -XGET "http://$(APP_HOST):$(APP_PORT)/datasets" | jq 'length'))
+	@if [ "$(LEN)" != "$(EXP_LEN)" ]; then echo "TEST ERROR: Expected '$(EXP_LEN)' datasets but saw: '$(LEN)'" ; exit 1; fi
+	@echo "PASSED: $@ "
+
+test-dataset-not-found: $(TEST_DEPS)
+	$(eval EXP_RES := 400)
+	$(eval RES := $(shell curl --silent --output /dev/stderr --write-out "%{http_code}" -XGET "http://$(APP_HOST):$(APP_PORT)/datasets/does-not-exist/$(DOY_START_TS)/$(DOY_END_TS)"))
+	@if [ "$(RES)" != "$(EXP_RES)" ]; then echo "TEST ERROR: Expected status code '$(EXP_RES)' but saw: '$(RES)'" ; exit 1; fi
+	@echo "PASSED: $@ "
+
+test-dataset-offline: $(TEST_DEPS)
+	$(eval EXP_RES := 404)
+	$(eval RES := $(shell curl --silent --output /dev/stderr --write-out "%{http_code}" -XGET "http://$(APP_HOST):$(APP_PORT)/datasets/test-monthly/$(MON_START_TS)/$(MON_END_TS)"))
+	@if [ "$(RES)" != "$(EXP_RES)" ]; then echo "TEST ERROR: Expected status code '$(EXP_RES)' but saw: '$(RES)'" ; exit 1; fi
+	@echo "PASSED: $@ "
+
+###
+### Tests for /admin
+###
+
+test-snapshot-catalog-refresh: $(TEST_DEPS)
+	$(eval EXP_REPO := $(TEST_REPO))
+	$(eval REPO := $(shell curl --silent -XPOST "http://$(APP_HOST):$(APP_PORT)/admin/snapshots/refresh?repo=$(TEST_REPO)" | jq -r '.[].repo'))
+	@if [ "$(REPO)" != "$(EXP_REPO)" ]; then echo "TEST ERROR: Expected repo '$(EXP_REPO)' in snapshot catalog but saw: '$(REPO)'" ; exit 1; fi
+	@echo "PASSED: $@ "
+
+test-startup-invalid-config:
+	$(eval EXP_CODE := 2)
+	@$(APP_CMD) --port 0 --es-host $(ES_HOST) --resolution fortnight --repo-pattern test/daily ; CODE=$$? ; \
+	if [ "$$CODE" != "$(EXP_CODE)" ]; then echo "TEST ERROR: Expected exit code '$(EXP_CODE)' but saw: '$$CODE'" ; exit 1; fi
+	@echo "PASSED: $@ "
+
+test-startup-missing-repo: $(ES_DEPS)
+	$(eval EXP_CODE := 4)
+	@$(APP_CMD) --port 0 --es-host $(ES_HOST) --resolution day --repo-pattern test-foo/daily/test-v1-%Y_%j ; CODE=$$? ; \
+	if [ "$$CODE" != "$(EXP_CODE)" ]; then echo "TEST ERROR: Expected exit code '$(EXP_CODE)' but saw: '$$CODE'" ; exit 1; fi
+	@echo "PASSED: $@ "